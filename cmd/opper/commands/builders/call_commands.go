@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/opper-ai/oppercli/cmd/opper/commands"
+	"github.com/opper-ai/oppercli/opperai"
 	"github.com/spf13/cobra"
 )
 
@@ -21,7 +22,13 @@ func BuildCallCommand(executeCommand func(commands.Command) error) *cobra.Comman
   echo "what is X?" | opper call myfunction "respond about X"
 
   # Call with tags
-  opper call myfunction "respond about X" "what is X?" --tags="env=prod,team=backend"`,
+  opper call myfunction "respond about X" "what is X?" --tags="env=prod,team=backend"
+
+  # Answer from the top 5 documents of an index, citing sources
+  opper call support "answer the question" "how do I reset my password?" --index helpdocs --top-k 5
+
+  # Restrict the index results and print the answer with sources as JSON
+  opper call support "answer the question" "pricing?" --index helpdocs --filter 'lang=en' --format json`,
 		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			model, _ := cmd.Flags().GetString("model")
@@ -51,17 +58,33 @@ func BuildCallCommand(executeCommand func(commands.Command) error) *cobra.Comman
 				input = string(stdinData)
 			}
 
+			index, _ := cmd.Flags().GetString("index")
+			topK, _ := cmd.Flags().GetInt("top-k")
+			filters, _ := cmd.Flags().GetStringArray("filter")
+			minScore, _ := cmd.Flags().GetFloat64("min-score")
+			format, _ := cmd.Flags().GetString("format")
+
 			return executeCommand(&commands.CallCommand{
 				Name:         args[0],
 				Instructions: args[1],
 				Input:        input,
 				Model:        model,
 				Tags:         tags,
+				Index:        index,
+				TopK:         topK,
+				Filters:      filters,
+				MinScore:     minScore,
+				Format:       format,
 			})
 		},
 	}
 	callCmd.Flags().String("model", "", "Custom model to use")
 	callCmd.Flags().String("tags", "", "Tags in the format key1=value1,key2=value2")
+	callCmd.Flags().String("index", "", "Answer using documents retrieved from this index")
+	callCmd.Flags().Int("top-k", opperai.DefaultRAGTopK, "Number of index results to use with --index")
+	callCmd.Flags().StringArray("filter", nil, "Filter index results, e.g. 'lang=en' (repeatable)")
+	callCmd.Flags().Float64("min-score", 0, "Ignore index results scoring below this")
+	callCmd.Flags().String("format", "text", "Output format with --index (text, json)")
 
 	return callCmd
 }
@@ -1,6 +1,8 @@
 package builders
 
 import (
+	"time"
+
 	"github.com/opper-ai/oppercli/cmd/opper/commands"
 	"github.com/spf13/cobra"
 )
@@ -33,3 +35,35 @@ func BuildDeletionCommand(use, short, resourceType string, executor func(string)
 	AddDeletionFlags(cmd)
 	return cmd
 }
+
+// AddPaginationFlags adds common flags for list commands backed by paginated endpoints
+func AddPaginationFlags(cmd *cobra.Command, defaultLimit int) {
+	cmd.Flags().Int("limit", defaultLimit, "Maximum number of items to return (0 for no limit)")
+	cmd.Flags().Bool("all", false, "Return all items, ignoring --limit")
+	cmd.Flags().Int("page-size", 100, "Number of items to fetch per request")
+}
+
+// GetPaginationFlags reads the flags added by AddPaginationFlags
+func GetPaginationFlags(cmd *cobra.Command) (limit int, pageSize int) {
+	limit, _ = cmd.Flags().GetInt("limit")
+	pageSize, _ = cmd.Flags().GetInt("page-size")
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		limit = 0
+	}
+	return limit, pageSize
+}
+
+// AddDirectoryUploadFlags adds flags for commands that upload a directory of files
+func AddDirectoryUploadFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("include", nil, "Only upload files matching this glob, e.g. '*.md' (repeatable)")
+	cmd.Flags().StringArray("exclude", nil, "Skip files and directories matching this glob, e.g. 'vendor/**' (repeatable)")
+	cmd.Flags().Int("concurrency", 4, "Number of files to upload in parallel")
+	cmd.Flags().Int("retries", 3, "Number of times to retry an upload after a network error, rate limit or server error")
+}
+
+// AddIndexWaitFlags adds flags for waiting until uploaded files are indexed
+func AddIndexWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "Wait until uploaded files have been indexed")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "Maximum time to wait for indexing")
+	cmd.Flags().Duration("interval", 2*time.Second, "Polling interval while waiting")
+}
@@ -0,0 +1,182 @@
+package builders
+
+import (
+	"github.com/opper-ai/oppercli/cmd/opper/commands"
+	"github.com/spf13/cobra"
+)
+
+func buildDatasetCommands(executeCommand func(commands.Command) error) *cobra.Command {
+	datasetCmd := &cobra.Command{
+		Use:   "dataset",
+		Short: "Manage function datasets",
+		Example: `  # List dataset entries of a function
+  opper functions dataset list myfunction
+
+  # Add an entry
+  opper functions dataset add myfunction --input "2+2" --expected "4"
+
+  # Import labelled data from JSONL or CSV
+  opper functions dataset import myfunction data.jsonl
+
+  # Export all entries as JSONL
+  opper functions dataset export myfunction > data.jsonl`,
+	}
+
+	// List command
+	listCmd := &cobra.Command{
+		Use:   "list <function>",
+		Short: "List dataset entries",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			limit, pageSize := GetPaginationFlags(cmd)
+			return executeCommand(&commands.ListDatasetEntriesCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				Limit:    limit,
+				PageSize: pageSize,
+			})
+		},
+	}
+	AddPaginationFlags(listCmd, 100)
+
+	// Get command
+	getCmd := &cobra.Command{
+		Use:   "get <function> <entry-uuid>",
+		Short: "Get a dataset entry",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeCommand(&commands.GetDatasetEntryCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				EntryUUID: args[1],
+			})
+		},
+	}
+
+	// Add command
+	addCmd := &cobra.Command{
+		Use:   "add <function>",
+		Short: "Add an entry to the dataset",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input, _ := cmd.Flags().GetString("input")
+			expected, _ := cmd.Flags().GetString("expected")
+			comment, _ := cmd.Flags().GetString("comment")
+			return executeCommand(&commands.AddDatasetEntryCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				Input:    input,
+				Expected: expected,
+				Comment:  comment,
+			})
+		},
+	}
+	addCmd.Flags().String("input", "", "Input of the entry")
+	addCmd.Flags().String("expected", "", "Expected output of the entry")
+	addCmd.Flags().String("comment", "", "Comment describing the entry")
+	addCmd.MarkFlagRequired("input")
+
+	// Update command
+	updateCmd := &cobra.Command{
+		Use:   "update <function> <entry-uuid>",
+		Short: "Update a dataset entry",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			changed := func(name string) *string {
+				if !cmd.Flags().Changed(name) {
+					return nil
+				}
+				value, _ := cmd.Flags().GetString(name)
+				return &value
+			}
+			return executeCommand(&commands.UpdateDatasetEntryCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				EntryUUID: args[1],
+				Input:     changed("input"),
+				Expected:  changed("expected"),
+				Comment:   changed("comment"),
+			})
+		},
+	}
+	updateCmd.Flags().String("input", "", "New input of the entry")
+	updateCmd.Flags().String("expected", "", "New expected output of the entry")
+	updateCmd.Flags().String("comment", "", "New comment of the entry")
+
+	// Delete command
+	deleteCmd := &cobra.Command{
+		Use:   "delete <function> <entry-uuid>",
+		Short: "Delete a dataset entry",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			force, _ := cmd.Flags().GetBool("yes")
+			confirmed, err := commands.ConfirmDeletion("dataset entry", args[1], force)
+			if err != nil || !confirmed {
+				return err
+			}
+			return executeCommand(&commands.DeleteDatasetEntryCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				EntryUUID: args[1],
+			})
+		},
+	}
+	AddDeletionFlags(deleteCmd)
+
+	// Import command
+	importCmd := &cobra.Command{
+		Use:   "import <function> <file>",
+		Short: "Import entries from a JSONL or CSV file (use - for stdin)",
+		Long: `Import entries from a JSONL or CSV file.
+
+JSONL lines are objects with "input", "expected" (or "output") and "comment"
+fields. CSV files must have a header row with input, expected and comment
+columns. The format is inferred from the file extension unless --format is set.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			return executeCommand(&commands.ImportDatasetCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				FilePath: args[1],
+				Format:   format,
+			})
+		},
+	}
+	importCmd.Flags().String("format", "", "File format (jsonl, csv)")
+
+	// Export command
+	exportCmd := &cobra.Command{
+		Use:   "export <function>",
+		Short: "Export all entries as JSONL",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, _ := cmd.Flags().GetString("out")
+			return executeCommand(&commands.ExportDatasetCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				OutPath: out,
+			})
+		},
+	}
+	exportCmd.Flags().String("out", "", "Write to file instead of stdout")
+
+	datasetCmd.AddCommand(
+		listCmd,
+		getCmd,
+		addCmd,
+		updateCmd,
+		deleteCmd,
+		importCmd,
+		exportCmd,
+	)
+
+	return datasetCmd
+}
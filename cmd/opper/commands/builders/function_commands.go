@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/opper-ai/oppercli/cmd/opper/commands"
 	"github.com/spf13/cobra"
@@ -30,15 +32,53 @@ func BuildFunctionCommands(executeCommand func(commands.Command) error) *cobra.C
 	listCmd := &cobra.Command{
 		Use:   "list [filter]",
 		Short: "List functions",
-		Args:  cobra.MaximumNArgs(1),
+		Example: `  # List functions under a path
+  opper functions list --path 'support/*'
+
+  # Find functions still running on a given model
+  opper functions list --model gpt-3.5-turbo --columns path,model,revision,uuid
+
+  # Functions with a dataset, most recently updated first
+  opper functions list --has-dataset --sort -updated`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			filter := ""
 			if len(args) > 0 {
 				filter = args[0]
 			}
-			return executeCommand(&commands.ListCommand{Filter: filter})
+			pathGlob, _ := cmd.Flags().GetString("path")
+			pathRegex, _ := cmd.Flags().GetString("regex")
+			project, _ := cmd.Flags().GetString("project")
+			model, _ := cmd.Flags().GetString("model")
+			metadata, _ := cmd.Flags().GetStringArray("metadata")
+			hasDataset, _ := cmd.Flags().GetBool("has-dataset")
+			sort, _ := cmd.Flags().GetString("sort")
+			columns, _ := cmd.Flags().GetStringSlice("columns")
+			limit, pageSize := GetPaginationFlags(cmd)
+			return executeCommand(&commands.ListCommand{
+				Filter:     filter,
+				PathGlob:   pathGlob,
+				PathRegex:  pathRegex,
+				Project:    project,
+				Model:      model,
+				Metadata:   metadata,
+				HasDataset: hasDataset,
+				Sort:       sort,
+				Columns:    columns,
+				Limit:      limit,
+				PageSize:   pageSize,
+			})
 		},
 	}
+	listCmd.Flags().String("path", "", "Filter by path glob (e.g. 'support/*')")
+	listCmd.Flags().String("regex", "", "Filter by path regular expression")
+	listCmd.Flags().String("project", "", "Filter by project name or UUID")
+	listCmd.Flags().String("model", "", "Filter by model")
+	listCmd.Flags().StringArray("metadata", nil, "Filter by metadata key=value (can be repeated)")
+	listCmd.Flags().Bool("has-dataset", false, "Only list functions with dataset entries")
+	listCmd.Flags().String("sort", "", "Sort by path, revision or updated (prefix with - for descending)")
+	listCmd.Flags().StringSlice("columns", nil, "Columns to show: path, description, model, revision, dataset, uuid, project, updated")
+	AddPaginationFlags(listCmd, 0)
 
 	// Create command
 	createCmd := &cobra.Command{
@@ -131,6 +171,63 @@ func BuildFunctionCommands(executeCommand func(commands.Command) error) *cobra.C
 		},
 	}
 
+	// History command
+	historyCmd := &cobra.Command{
+		Use:   "history <name>",
+		Short: "List revisions of a function",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeCommand(&commands.FunctionHistoryCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+			})
+		},
+	}
+
+	// Diff command
+	diffCmd := &cobra.Command{
+		Use:   "diff <name>",
+		Short: "Show changes between two revisions of a function",
+		Example: `  # Compare the current revision with the previous one
+  opper functions diff myfunction
+
+  # Compare two specific revisions
+  opper functions diff myfunction --from 3 --to 5`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, _ := cmd.Flags().GetInt("from")
+			to, _ := cmd.Flags().GetInt("to")
+			return executeCommand(&commands.FunctionDiffCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				From: from,
+				To:   to,
+			})
+		},
+	}
+	diffCmd.Flags().Int("from", 0, "Revision to compare from (default: the revision before --to)")
+	diffCmd.Flags().Int("to", 0, "Revision to compare to (default: current revision)")
+
+	// Rollback command
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback <name>",
+		Short: "Restore a function to an earlier revision",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			to, _ := cmd.Flags().GetInt("to")
+			return executeCommand(&commands.FunctionRollbackCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				To: to,
+			})
+		},
+	}
+	rollbackCmd.Flags().Int("to", 0, "Revision to restore")
+	rollbackCmd.MarkFlagRequired("to")
+
 	// Evaluations command
 	evaluationsCmd := &cobra.Command{
 		Use:   "evaluations",
@@ -143,35 +240,140 @@ func BuildFunctionCommands(executeCommand func(commands.Command) error) *cobra.C
 		Short: "List evaluations for a function",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			limit, _ := cmd.Flags().GetInt("limit")
+			limit, pageSize := GetPaginationFlags(cmd)
 			return executeCommand(&commands.ListEvaluationsCommand{
 				BaseCommand: commands.BaseCommand{
 					FunctionPath: args[0],
 				},
-				Limit: limit,
+				Limit:    limit,
+				PageSize: pageSize,
 			})
 		},
 	}
-
-	listEvaluationsCmd.Flags().Int("limit", 0, "Limit the number of evaluations returned")
+	AddPaginationFlags(listEvaluationsCmd, 0)
 
 	// Run evaluation command
 	runEvaluationCmd := &cobra.Command{
 		Use:   "run <name>",
 		Short: "Run an evaluation for a function",
-		Args:  cobra.ExactArgs(1),
+		Example: `  # Start an evaluation and return immediately
+  opper functions evaluations run myfunction
+
+  # Wait for the evaluation and fail if the average score is below 0.8
+  opper functions evaluations run myfunction --wait --min opper.score=0.8
+
+  # Try a candidate model and prompt without changing the function
+  opper functions evaluations run myfunction --model anthropic/claude-3.5-sonnet --instructions-file new.txt --few-shot 3`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			wait, _ := cmd.Flags().GetBool("wait")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			interval, _ := cmd.Flags().GetDuration("interval")
+			minAvgFlags, _ := cmd.Flags().GetStringArray("min")
+			minMedianFlags, _ := cmd.Flags().GetStringArray("min-median")
+
+			minAvg, err := parseThresholds(minAvgFlags)
+			if err != nil {
+				return err
+			}
+			minMedian, err := parseThresholds(minMedianFlags)
+			if err != nil {
+				return err
+			}
+
+			model, _ := cmd.Flags().GetString("model")
+			instructionsFile, _ := cmd.Flags().GetString("instructions-file")
+			var fewShot *int
+			if cmd.Flags().Changed("few-shot") {
+				count, _ := cmd.Flags().GetInt("few-shot")
+				fewShot = &count
+			}
+
 			return executeCommand(&commands.RunEvaluationCommand{
 				BaseCommand: commands.BaseCommand{
 					FunctionPath: args[0],
 				},
+				Model:            model,
+				InstructionsFile: instructionsFile,
+				FewShotCount:     fewShot,
+				Wait:             wait,
+				Timeout:          timeout,
+				Interval:         interval,
+				MinAvg:           minAvg,
+				MinMedian:        minMedian,
+			})
+		},
+	}
+	runEvaluationCmd.Flags().String("model", "", "Run the evaluation with this model instead of the function's")
+	runEvaluationCmd.Flags().String("instructions-file", "", "Run the evaluation with instructions read from this file")
+	runEvaluationCmd.Flags().Int("few-shot", 0, "Run the evaluation with this few-shot count")
+	runEvaluationCmd.Flags().Bool("wait", false, "Wait for the evaluation to complete and print summary statistics")
+	runEvaluationCmd.Flags().Duration("timeout", 30*time.Minute, "Maximum time to wait for the evaluation")
+	runEvaluationCmd.Flags().Duration("interval", 5*time.Second, "Polling interval while waiting")
+	runEvaluationCmd.Flags().StringArray("min", nil, "Minimum average for a dimension, e.g. opper.score=0.8 (implies --wait, can be repeated)")
+	runEvaluationCmd.Flags().StringArray("min-median", nil, "Minimum median for a dimension, e.g. opper.score=0.8 (implies --wait, can be repeated)")
+
+	// Compare evaluations command
+	compareEvaluationsCmd := &cobra.Command{
+		Use:   "compare <name> <evaluation-a> <evaluation-b>",
+		Short: "Compare two evaluation runs record by record",
+		Example: `  # Compare two runs in the terminal
+  opper functions evaluations compare myfunction <eval-a> <eval-b>
+
+  # Produce a Markdown summary for a PR comment
+  opper functions evaluations compare myfunction <eval-a> <eval-b> --format markdown`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			maxRegressions, _ := cmd.Flags().GetInt("max-regressions")
+			resamples, _ := cmd.Flags().GetInt("resamples")
+			return executeCommand(&commands.CompareEvaluationsCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				EvaluationA:    args[1],
+				EvaluationB:    args[2],
+				Format:         format,
+				MaxRegressions: maxRegressions,
+				Resamples:      resamples,
 			})
 		},
 	}
+	compareEvaluationsCmd.Flags().String("format", "table", "Output format (table, json, markdown)")
+	compareEvaluationsCmd.Flags().Int("max-regressions", 10, "Maximum number of regressed records to show (-1 for all)")
+	compareEvaluationsCmd.Flags().Int("resamples", 10000, "Number of bootstrap resamples for significance")
+
+	// Export evaluation command
+	exportEvaluationCmd := &cobra.Command{
+		Use:   "export <name> <evaluation-uuid>",
+		Short: "Export evaluation records as CSV, JSONL or an HTML report",
+		Example: `  # Export records to CSV
+  opper functions evaluations export myfunction <eval-uuid> > records.csv
+
+  # Create a self-contained HTML report
+  opper functions evaluations export myfunction <eval-uuid> --format html --out report.html`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			out, _ := cmd.Flags().GetString("out")
+			return executeCommand(&commands.ExportEvaluationCommand{
+				BaseCommand: commands.BaseCommand{
+					FunctionPath: args[0],
+				},
+				EvaluationUUID: args[1],
+				Format:         format,
+				OutPath:        out,
+			})
+		},
+	}
+	exportEvaluationCmd.Flags().String("format", "csv", "Output format (csv, jsonl, html)")
+	exportEvaluationCmd.Flags().String("out", "", "Write to file instead of stdout")
 
 	evaluationsCmd.AddCommand(
 		listEvaluationsCmd,
 		runEvaluationCmd,
+		compareEvaluationsCmd,
+		exportEvaluationCmd,
 	)
 
 	functionsCmd.AddCommand(
@@ -180,8 +382,29 @@ func BuildFunctionCommands(executeCommand func(commands.Command) error) *cobra.C
 		deleteCmd,
 		getCmd,
 		chatCmd,
+		historyCmd,
+		diffCmd,
+		rollbackCmd,
+		buildDatasetCommands(executeCommand),
 		evaluationsCmd,
 	)
 
 	return functionsCmd
 }
+
+// parseThresholds parses dimension=value pairs into a map
+func parseThresholds(pairs []string) (map[string]float64, error) {
+	thresholds := make(map[string]float64, len(pairs))
+	for _, pair := range pairs {
+		i := strings.LastIndex(pair, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid threshold %q, expected dimension=value", pair)
+		}
+		value, err := strconv.ParseFloat(pair[i+1:], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold value in %q: %w", pair, err)
+		}
+		thresholds[pair[:i]] = value
+	}
+	return thresholds, nil
+}
@@ -1,7 +1,12 @@
 package builders
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/opper-ai/oppercli/cmd/opper/commands"
+	"github.com/opper-ai/oppercli/opperai/chunking"
 	"github.com/spf13/cobra"
 )
 
@@ -62,36 +67,152 @@ func BuildIndexCommands(executeCommand func(commands.Command) error) *cobra.Comm
 	queryCmd := &cobra.Command{
 		Use:   "query <name> <query> [filter_json]",
 		Short: "Query an index",
-		Args:  cobra.RangeArgs(2, 3),
+		Example: `  # Query an index
+  opper indexes query myindex "how do I configure retries"
+
+  # Only return Go documents from 2023 onwards
+  opper indexes query myindex "retries" --filter 'lang=go' --filter 'year>=2023'
+
+  # Match any of several values, or pass filters as JSON
+  opper indexes query myindex "retries" --filter 'tenant in acme,globex'
+  opper indexes query myindex "retries" '[{"field":"tenant","operation":"=","value":"acme"}]'
+
+  # Show metadata fields as columns
+  opper indexes query myindex "retries" --top-k 5 --columns score,key,metadata.lang,content
+
+  # Structured output for scripts
+  opper indexes query myindex "retries" --format json`,
+		Args: cobra.RangeArgs(2, 3),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			filter := "{}"
+			filters, _ := cmd.Flags().GetStringArray("filter")
+			topK, _ := cmd.Flags().GetInt("top-k")
+			format, _ := cmd.Flags().GetString("format")
+			columns, _ := cmd.Flags().GetStringSlice("columns")
 			if len(args) > 2 {
-				filter = args[2]
+				filters = append(filters, args[2])
 			}
 			return executeCommand(&commands.QueryIndexCommand{
-				Name:   args[0],
-				Query:  args[1],
-				Filter: filter,
+				Name:    args[0],
+				Query:   args[1],
+				Filters: filters,
+				TopK:    topK,
+				Format:  format,
+				Columns: columns,
 			})
 		},
 	}
+	queryCmd.Flags().StringArray("filter", nil, "Filter as <field><op><value> with op =, !=, >, >=, <, <=, 'in' or 'not in', or a JSON list of filters (repeatable)")
+	queryCmd.Flags().Int("top-k", 0, "Maximum number of results to return (default: server default)")
+	queryCmd.Flags().String("format", "table", "Output format (table, json, plain)")
+	queryCmd.Flags().StringSlice("columns", nil, "Table columns: score, key, content, metadata or metadata.<field> (default score,key,content)")
 
 	// Get command
 	getCmd := &cobra.Command{
 		Use:   "get <name>",
 		Short: "Get index details",
-		Args:  cobra.ExactArgs(1),
+		Example: `  # Show an index with a summary of file statuses
+  opper indexes get myindex
+
+  # List failed files with their reasons
+  opper indexes get myindex --status failed
+
+  # The largest PDFs first
+  opper indexes get myindex --match '*.pdf' --sort size --limit 20
+
+  # Refresh until every file is indexed
+  opper indexes get myindex --watch`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return executeCommand(&commands.GetIndexCommand{Name: args[0]})
+			status, _ := cmd.Flags().GetStringArray("status")
+			match, _ := cmd.Flags().GetString("match")
+			sortBy, _ := cmd.Flags().GetString("sort")
+			reverse, _ := cmd.Flags().GetBool("reverse")
+			limit, _ := cmd.Flags().GetInt("limit")
+			watch, _ := cmd.Flags().GetBool("watch")
+			interval, _ := cmd.Flags().GetDuration("interval")
+			return executeCommand(&commands.GetIndexCommand{
+				Name:     args[0],
+				Status:   status,
+				Match:    match,
+				Sort:     sortBy,
+				Reverse:  reverse,
+				Limit:    limit,
+				Watch:    watch,
+				Interval: interval,
+			})
 		},
 	}
+	getCmd.Flags().StringArray("status", nil, "Only list files with this index status, e.g. failed or pending,processing (repeatable)")
+	getCmd.Flags().String("match", "", "Only list files whose name matches this glob, e.g. '*.pdf' or 'guides/**'")
+	getCmd.Flags().String("sort", "name", "Sort files by name, size (largest first), date (newest first) or status (failed first)")
+	getCmd.Flags().Bool("reverse", false, "Reverse the sort order")
+	getCmd.Flags().Int("limit", 50, "Maximum number of files to list (0 for all)")
+	getCmd.Flags().Bool("watch", false, "Refresh until indexing completes")
+	getCmd.Flags().Duration("interval", 2*time.Second, "Refresh interval with --watch")
 
 	// Add command
 	addCmd := &cobra.Command{
-		Use:   "add <name> <key> <content> [metadata_json]",
+		Use:   "add <name> [<key> <content> [metadata_json]]",
 		Short: "Add content to an index",
-		Args:  cobra.RangeArgs(3, 4),
+		Example: `  # Add a single document
+  opper indexes add myindex intro "Opper is a platform for..." '{"lang":"en"}'
+
+  # Add documents from a JSONL file with key, content and metadata fields
+  opper indexes add myindex --from docs.jsonl --batch 100
+
+  # Split a source tree into chunks and add them as documents
+  opper indexes add myindex --chunk ./src --recursive --include '*.go' --chunk-size 1200
+
+  # Preview the chunks of a Markdown file without adding them
+  opper indexes add myindex --chunk guide.md --dry-run`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			from, _ := cmd.Flags().GetString("from")
+			chunk, _ := cmd.Flags().GetString("chunk")
+			if from != "" && chunk != "" {
+				return fmt.Errorf("--from and --chunk cannot be used together")
+			}
+			if from != "" || chunk != "" {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			return cobra.RangeArgs(3, 4)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			from, _ := cmd.Flags().GetString("from")
+			batch, _ := cmd.Flags().GetInt("batch")
+			if from != "" {
+				return executeCommand(&commands.AddToIndexCommand{
+					Name:  args[0],
+					From:  from,
+					Batch: batch,
+				})
+			}
+
+			if chunk, _ := cmd.Flags().GetString("chunk"); chunk != "" {
+				strategy, _ := cmd.Flags().GetString("strategy")
+				size, _ := cmd.Flags().GetInt("chunk-size")
+				overlap, _ := cmd.Flags().GetInt("chunk-overlap")
+				recursive, _ := cmd.Flags().GetBool("recursive")
+				include, _ := cmd.Flags().GetStringArray("include")
+				exclude, _ := cmd.Flags().GetStringArray("exclude")
+				replace, _ := cmd.Flags().GetBool("replace")
+				dryRun, _ := cmd.Flags().GetBool("dry-run")
+				metadata, _ := cmd.Flags().GetString("metadata")
+				return executeCommand(&commands.AddToIndexCommand{
+					Name:         args[0],
+					Metadata:     metadata,
+					Chunk:        chunk,
+					Strategy:     strategy,
+					ChunkSize:    size,
+					ChunkOverlap: overlap,
+					Recursive:    recursive,
+					Include:      include,
+					Exclude:      exclude,
+					Batch:        batch,
+					Replace:      replace,
+					DryRun:       dryRun,
+				})
+			}
+
 			metadata := "{}"
 			if len(args) > 3 {
 				metadata = args[3]
@@ -104,19 +225,107 @@ func BuildIndexCommands(executeCommand func(commands.Command) error) *cobra.Comm
 			})
 		},
 	}
+	addCmd.Flags().String("from", "", "Add documents from a JSONL file ('-' for stdin)")
+	addCmd.Flags().Int("batch", 100, "Number of documents per request when using --from or --chunk")
+	addCmd.Flags().String("chunk", "", "Split a local file or directory into chunks and add each as a document")
+	addCmd.Flags().String("strategy", "auto", "Chunking strategy: auto (by file extension), text, markdown or code")
+	addCmd.Flags().Int("chunk-size", chunking.DefaultSize, fmt.Sprintf("Maximum chunk size in characters (at least %d)", chunking.MinSize))
+	addCmd.Flags().Int("chunk-overlap", chunking.DefaultOverlap, "Characters of trailing lines repeated at the start of the next chunk")
+	addCmd.Flags().BoolP("recursive", "r", false, "Include files in subdirectories when chunking a directory")
+	addCmd.Flags().StringArray("include", nil, "Only chunk files matching this glob, e.g. '*.md' (repeatable)")
+	addCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching this glob, e.g. 'vendor/**' (repeatable)")
+	addCmd.Flags().Bool("replace", false, "After adding, delete chunks left over from earlier runs of each file")
+	addCmd.Flags().Bool("dry-run", false, "Print the chunks as JSONL instead of adding them")
+	addCmd.Flags().String("metadata", "", "JSON metadata added to every chunk")
 
 	// Upload command
 	uploadCmd := &cobra.Command{
-		Use:   "upload <name> <file_path>",
-		Short: "Upload and index a file",
-		Args:  cobra.ExactArgs(2),
+		Use:   "upload <name> <path>",
+		Short: "Upload and index a file or directory",
+		Example: `  # Upload a single file
+  opper indexes upload myindex guide.pdf
+
+  # Upload the Markdown files in a documentation tree
+  opper indexes upload myindex docs/ --recursive --include '*.md' --exclude 'vendor/**' --concurrency 8
+
+  # Block until the uploaded files can be queried
+  opper indexes upload myindex guide.pdf --wait --timeout 5m`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			recursive, _ := cmd.Flags().GetBool("recursive")
+			include, _ := cmd.Flags().GetStringArray("include")
+			exclude, _ := cmd.Flags().GetStringArray("exclude")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			retries, _ := cmd.Flags().GetInt("retries")
+			wait, _ := cmd.Flags().GetBool("wait")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			interval, _ := cmd.Flags().GetDuration("interval")
 			return executeCommand(&commands.UploadToIndexCommand{
-				Name:     args[0],
-				FilePath: args[1],
+				Name:        args[0],
+				FilePath:    args[1],
+				Recursive:   recursive,
+				Include:     include,
+				Exclude:     exclude,
+				Concurrency: concurrency,
+				Retries:     retries,
+				Wait:        wait,
+				Timeout:     timeout,
+				Interval:    interval,
 			})
 		},
 	}
+	uploadCmd.Flags().BoolP("recursive", "r", false, "Include files in subdirectories")
+	AddDirectoryUploadFlags(uploadCmd)
+	AddIndexWaitFlags(uploadCmd)
+
+	// Sync command
+	syncCmd := &cobra.Command{
+		Use:   "sync <name> <dir>",
+		Short: "Make an index mirror a local directory",
+		Long: `Upload new and changed files from a directory tree and optionally delete
+files that no longer exist locally.
+
+Content hashes of uploaded files are kept in a manifest (by default
+.opper-sync-<name>.json in the directory), so later runs only upload what
+changed. Files uploaded by other means are uploaded again on the first sync.`,
+		Example: `  # Preview what would change
+  opper indexes sync docs ./site --dry-run
+
+  # Mirror the Markdown files of a docs tree, removing deleted pages
+  opper indexes sync docs ./site --include '*.md' --delete`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			include, _ := cmd.Flags().GetStringArray("include")
+			exclude, _ := cmd.Flags().GetStringArray("exclude")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			retries, _ := cmd.Flags().GetInt("retries")
+			wait, _ := cmd.Flags().GetBool("wait")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			interval, _ := cmd.Flags().GetDuration("interval")
+			deleteMissing, _ := cmd.Flags().GetBool("delete")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			manifest, _ := cmd.Flags().GetString("manifest")
+			return executeCommand(&commands.SyncIndexCommand{
+				Name:        args[0],
+				Dir:         args[1],
+				Include:     include,
+				Exclude:     exclude,
+				Delete:      deleteMissing,
+				DryRun:      dryRun,
+				Manifest:    manifest,
+				Concurrency: concurrency,
+				Retries:     retries,
+				Wait:        wait,
+				Timeout:     timeout,
+				Interval:    interval,
+			})
+		},
+	}
+	AddDirectoryUploadFlags(syncCmd)
+	AddIndexWaitFlags(syncCmd)
+	syncCmd.Flags().Bool("delete", false, "Delete files from the index that no longer exist locally")
+	syncCmd.Flags().Bool("dry-run", false, "Show what would change without uploading or deleting")
+	syncCmd.Flags().String("manifest", "", "Path of the sync manifest (default <dir>/.opper-sync-<name>.json)")
 
 	indexesCmd.AddCommand(
 		listCmd,
@@ -126,7 +335,228 @@ func BuildIndexCommands(executeCommand func(commands.Command) error) *cobra.Comm
 		getCmd,
 		addCmd,
 		uploadCmd,
+		syncCmd,
+		buildIndexDocumentCommands(executeCommand),
 	)
+	indexesCmd.AddCommand(buildIndexTransferCommands(executeCommand)...)
+	indexesCmd.AddCommand(buildIndexEvalCommand(executeCommand))
 
 	return indexesCmd
 }
+
+func buildIndexDocumentCommands(executeCommand func(commands.Command) error) *cobra.Command {
+	documentsCmd := &cobra.Command{
+		Use:     "documents",
+		Aliases: []string{"docs"},
+		Short:   "Manage documents in an index",
+		Example: `  # List documents
+  opper indexes documents list myindex
+
+  # Show a document
+  opper indexes documents get myindex intro
+
+  # Delete a document, or every document matching filters
+  opper indexes documents delete myindex intro
+  opper indexes documents delete myindex --filter 'tenant=acme'`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list <name>",
+		Short: "List documents in an index",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			limit, pageSize := GetPaginationFlags(cmd)
+			format, _ := cmd.Flags().GetString("format")
+			return executeCommand(&commands.ListIndexDocumentsCommand{
+				Name:     args[0],
+				Limit:    limit,
+				PageSize: pageSize,
+				Format:   format,
+			})
+		},
+	}
+	AddPaginationFlags(listCmd, 50)
+	listCmd.Flags().String("format", "table", "Output format (table, jsonl)")
+
+	getCmd := &cobra.Command{
+		Use:   "get <name> <key>",
+		Short: "Show a document",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			return executeCommand(&commands.GetIndexDocumentCommand{
+				Name:   args[0],
+				Key:    args[1],
+				Format: format,
+			})
+		},
+	}
+	getCmd.Flags().String("format", "text", "Output format (text, json)")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name> [key]",
+		Short: "Delete a document by key, or all documents matching --filter",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filters, _ := cmd.Flags().GetStringArray("filter")
+			force, _ := cmd.Flags().GetBool("yes")
+
+			var key, target string
+			switch {
+			case len(args) == 2 && len(filters) > 0:
+				return fmt.Errorf("specify either a key or --filter, not both")
+			case len(args) == 2:
+				key = args[1]
+				target = key
+			case len(filters) > 0:
+				target = strings.Join(filters, " and ")
+			default:
+				return fmt.Errorf("specify a document key or at least one --filter")
+			}
+
+			resourceType := "document"
+			if key == "" {
+				resourceType = "all documents matching"
+			}
+			confirmed, err := commands.ConfirmDeletion(resourceType, target, force)
+			if err != nil || !confirmed {
+				return err
+			}
+
+			return executeCommand(&commands.DeleteIndexDocumentsCommand{
+				Name:    args[0],
+				Key:     key,
+				Filters: filters,
+			})
+		},
+	}
+	AddDeletionFlags(deleteCmd)
+	deleteCmd.Flags().StringArray("filter", nil, "Delete documents matching this filter, e.g. 'tenant=acme' (repeatable)")
+
+	documentsCmd.AddCommand(listCmd, getCmd, deleteCmd)
+	return documentsCmd
+}
+
+func buildIndexTransferCommands(executeCommand func(commands.Command) error) []*cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Export the documents and file list of an index as JSONL",
+		Long: `Export every document of an index, with keys and metadata, followed by the
+list of files uploaded to it. File contents cannot be downloaded, so files
+are listed for reference only.`,
+		Example: `  # Back up an index
+  opper indexes export myindex > dump.jsonl
+
+  # Export from another account
+  opper indexes export myindex --key prod -o prod-dump.jsonl`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, _ := cmd.Flags().GetString("output")
+			return executeCommand(&commands.ExportIndexCommand{
+				Name:   args[0],
+				Output: out,
+			})
+		},
+	}
+	exportCmd.Flags().StringP("output", "o", "", "Write the export to a file instead of stdout")
+
+	importCmd := &cobra.Command{
+		Use:   "import <name> <file>",
+		Short: "Import documents from an export into an index",
+		Long: `Add the documents of an export to an index, creating the index if it does
+not exist. Documents with an existing key are replaced. Plain document JSONL,
+as written by 'opper indexes documents list --format jsonl', is accepted too.`,
+		Example: `  # Restore a backup
+  opper indexes import myindex dump.jsonl
+
+  # Read the export from stdin
+  cat dump.jsonl | opper indexes import myindex -`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			batch, _ := cmd.Flags().GetInt("batch")
+			return executeCommand(&commands.ImportIndexCommand{
+				Name:  args[0],
+				File:  args[1],
+				Batch: batch,
+			})
+		},
+	}
+	importCmd.Flags().Int("batch", 100, "Number of documents per request")
+
+	copyCmd := &cobra.Command{
+		Use:   "copy <source> <destination>",
+		Short: "Copy the documents of an index to another index",
+		Long: `Copy every document of an index into another index, creating it if it does
+not exist. The source is read with the account selected by --key and the
+destination written with the account selected by --to-key, which defaults
+to the same account. Uploaded files are not copied.`,
+		Example: `  # Seed staging from production
+  opper indexes copy docs docs --key prod --to-key staging
+
+  # Duplicate an index within an account
+  opper indexes copy docs docs-backup`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			toKey, _ := cmd.Flags().GetString("to-key")
+			batch, _ := cmd.Flags().GetInt("batch")
+			return executeCommand(&commands.CopyIndexCommand{
+				Source:  args[0],
+				Dest:    args[1],
+				DestKey: toKey,
+				Batch:   batch,
+			})
+		},
+	}
+	copyCmd.Flags().String("to-key", "", "API key from config to write the destination with (default: same as --key)")
+	copyCmd.Flags().Int("batch", 100, "Number of documents per request")
+
+	return []*cobra.Command{exportCmd, importCmd, copyCmd}
+}
+
+func buildIndexEvalCommand(executeCommand func(commands.Command) error) *cobra.Command {
+	evalCmd := &cobra.Command{
+		Use:   "eval <name>",
+		Short: "Measure retrieval quality against a set of judged queries",
+		Long: `Run queries with known relevant documents against an index and report
+recall@k, precision@k, MRR and nDCG@k, with the missed documents per query.
+
+The queries file has one JSON object per line:
+
+  {"id": "install", "query": "how do I install?", "relevant": ["install.md", "faq.md#2"]}
+
+"relevant" may also map keys to relevance grades for nDCG, e.g. {"install.md": 2}.
+A result matches a relevant key by exact key, as a chunk of it
+("install.md#3" matches "install.md") or by its "source" metadata, so the
+same queries keep working when documents are chunked differently.`,
+		Example: `  # Evaluate the top 5 results per query
+  opper indexes eval docs --queries qrels.jsonl --k 5
+
+  # Save a run and compare a later one against it
+  opper indexes eval docs --queries qrels.jsonl --format json > before.json
+  opper indexes eval docs --queries qrels.jsonl --baseline before.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			queries, _ := cmd.Flags().GetString("queries")
+			k, _ := cmd.Flags().GetInt("k")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			format, _ := cmd.Flags().GetString("format")
+			baseline, _ := cmd.Flags().GetString("baseline")
+			return executeCommand(&commands.EvalIndexCommand{
+				Name:        args[0],
+				Queries:     queries,
+				K:           k,
+				Concurrency: concurrency,
+				Format:      format,
+				Baseline:    baseline,
+			})
+		},
+	}
+	evalCmd.Flags().String("queries", "", "JSONL file of queries with relevant document keys")
+	evalCmd.MarkFlagRequired("queries")
+	evalCmd.Flags().Int("k", 10, "Number of results retrieved per query")
+	evalCmd.Flags().Int("concurrency", 4, "Number of queries to run in parallel")
+	evalCmd.Flags().String("format", "table", "Output format (table, json)")
+	evalCmd.Flags().String("baseline", "", "JSON output of an earlier run to compare against")
+
+	return evalCmd
+}
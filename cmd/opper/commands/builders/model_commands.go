@@ -1,7 +1,14 @@
 package builders
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/opper-ai/oppercli/cmd/opper/commands"
+	"github.com/opper-ai/oppercli/opperai"
 	"github.com/spf13/cobra"
 )
 
@@ -12,11 +19,14 @@ func BuildModelCommands(executeCommand func(commands.Command) error) *cobra.Comm
 		Example: `  # List all models
   opper models list
 
-  # Create a new model
-  opper models create mymodel litellm-id api-key
+  # Create a new model, prompting for its API key
+  opper models create mymodel litellm-id
 
   # Test a model
-  opper models test mymodel`,
+  opper models test mymodel
+
+  # Benchmark a model
+  opper models bench mymodel`,
 	}
 
 	// List command
@@ -35,22 +45,151 @@ func BuildModelCommands(executeCommand func(commands.Command) error) *cobra.Comm
 
 	// Create command
 	createCmd := &cobra.Command{
-		Use:   "create <name> <litellm-id> <api-key> [extra_json]",
+		Use:   "create <name> <litellm-id> [api-key] [extra_json]",
 		Short: "Create a new model",
-		Args:  cobra.RangeArgs(3, 4),
+		Long: `Create a custom model backed by a LiteLLM identifier.
+
+The provider API key is prompted for without echo unless it is read from a
+file with --api-key-file or from an environment variable with --api-key-env.
+Passing it as an argument still works but leaves it in shell history and
+process listings.`,
+		Example: `  # Prompt for the API key
+  opper models create my-gpt4 azure/my-gpt4-deployment --extra-file azure.json
+
+  # Read the key from an environment variable or a secrets file
+  opper models create my-gpt4 azure/my-gpt4-deployment --api-key-env AZURE_API_KEY '{"api_version": "2024-06-01"}'
+  opper models create my-gpt4 azure/my-gpt4-deployment --api-key-file /run/secrets/azure-key`,
+		Args: cobra.RangeArgs(2, 4),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			extra := "{}"
-			if len(args) > 3 {
-				extra = args[3]
+			keyFile, _ := cmd.Flags().GetString("api-key-file")
+			keyEnv, _ := cmd.Flags().GetString("api-key-env")
+			extraFile, _ := cmd.Flags().GetString("extra-file")
+
+			// With a key flag the third argument is the extra JSON
+			rest := args[2:]
+			var apiKey, extra string
+			if keyFile == "" && keyEnv == "" && len(rest) > 0 {
+				var obj map[string]interface{}
+				if json.Unmarshal([]byte(rest[0]), &obj) == nil {
+					return fmt.Errorf("the third argument is the API key but looks like extra JSON; pass the key with --api-key-env or --api-key-file, or omit it to be prompted and use --extra-file for the JSON")
+				}
+				apiKey, rest = rest[0], rest[1:]
+				fmt.Fprintln(os.Stderr, "Warning: passing the API key as an argument exposes it in shell history and process listings; use --api-key-file, --api-key-env or the prompt instead")
+			}
+			switch len(rest) {
+			case 0:
+			case 1:
+				extra = rest[0]
+			default:
+				return fmt.Errorf("too many arguments: the API key cannot be given both as an argument and with a flag")
 			}
+
 			return executeCommand(&commands.CreateModelCommand{
 				Name:       args[0],
 				Identifier: args[1],
-				APIKey:     args[2],
+				APIKey:     apiKey,
 				Extra:      extra,
+				APIKeyFile: keyFile,
+				APIKeyEnv:  keyEnv,
+				ExtraFile:  extraFile,
+			})
+		},
+	}
+	createCmd.Flags().String("api-key-file", "", "Read the API key from a file ('-' for stdin)")
+	createCmd.Flags().String("api-key-env", "", "Read the API key from an environment variable")
+	createCmd.Flags().String("extra-file", "", "Read the extra JSON configuration from a file ('-' for stdin)")
+
+	// Update command
+	updateCmd := &cobra.Command{
+		Use:   "update <name>",
+		Short: "Update a model",
+		Long: `Change the identifier, name, extra configuration or API key of a custom
+model. Only the given fields are changed; the API key is kept unless a key
+flag is used.`,
+		Example: `  # Point a model at a new deployment
+  opper models update my-gpt4 --identifier azure/my-gpt4-deployment-v2
+
+  # Rotate the API key from an environment variable
+  opper models update my-gpt4 --api-key-env AZURE_API_KEY
+
+  # Replace the extra configuration
+  opper models update my-gpt4 --extra-file azure.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			identifier, _ := cmd.Flags().GetString("identifier")
+			rename, _ := cmd.Flags().GetString("rename")
+			keyFile, _ := cmd.Flags().GetString("api-key-file")
+			keyEnv, _ := cmd.Flags().GetString("api-key-env")
+			prompt, _ := cmd.Flags().GetBool("prompt-api-key")
+			extra, _ := cmd.Flags().GetString("extra")
+			extraFile, _ := cmd.Flags().GetString("extra-file")
+			return executeCommand(&commands.UpdateModelCommand{
+				Name:         args[0],
+				Rename:       rename,
+				Identifier:   identifier,
+				APIKeyFile:   keyFile,
+				APIKeyEnv:    keyEnv,
+				PromptAPIKey: prompt,
+				Extra:        extra,
+				ExtraFile:    extraFile,
+			})
+		},
+	}
+	updateCmd.Flags().String("identifier", "", "New LiteLLM identifier")
+	updateCmd.Flags().String("rename", "", "New name for the model")
+	updateCmd.Flags().String("api-key-file", "", "Read a new API key from a file ('-' for stdin)")
+	updateCmd.Flags().String("api-key-env", "", "Read a new API key from an environment variable")
+	updateCmd.Flags().Bool("prompt-api-key", false, "Prompt for a new API key without echo")
+	updateCmd.Flags().String("extra", "", "New extra JSON configuration, replacing the current one")
+	updateCmd.Flags().String("extra-file", "", "Read the new extra JSON configuration from a file ('-' for stdin)")
+
+	// Apply command
+	applyCmd := &cobra.Command{
+		Use:   "apply <file>",
+		Short: "Create and update models to match a YAML file",
+		Long: `Reconcile custom models with a YAML file that can be kept in version control:
+
+  models:
+    - name: prod/gpt4
+      identifier: azure/${AZURE_DEPLOYMENT}
+      api_key_env: AZURE_API_KEY
+      extra:
+        api_base: https://my-endpoint.openai.azure.com/
+        api_version: "2024-06-01"
+    - name: prod/claude
+      identifier: bedrock/anthropic.claude-3-sonnet-20240229-v1:0
+      api_key_file: secrets/bedrock.key
+
+Missing models are created and models whose identifier or extra differ are
+updated. ${VAR} in identifiers and extra values is replaced with the
+environment variable. API keys are never stored in the file: they are read
+from api_key_env or api_key_file when a model is created, or for every model
+with --update-keys, since existing keys cannot be compared. Models not in the
+file are only deleted with --prune.`,
+		Example: `  # Preview the changes
+  opper models apply models.yaml --dry-run
+
+  # Apply and delete models that are no longer listed
+  opper models apply models.yaml --prune --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prune, _ := cmd.Flags().GetBool("prune")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			updateKeys, _ := cmd.Flags().GetBool("update-keys")
+			yes, _ := cmd.Flags().GetBool("yes")
+			return executeCommand(&commands.ApplyModelsCommand{
+				File:       args[0],
+				Prune:      prune,
+				DryRun:     dryRun,
+				UpdateKeys: updateKeys,
+				Yes:        yes,
 			})
 		},
 	}
+	applyCmd.Flags().Bool("prune", false, "Delete custom models that are not in the file")
+	applyCmd.Flags().Bool("dry-run", false, "Show the changes without making them")
+	applyCmd.Flags().Bool("update-keys", false, "Send API keys for existing models too")
+	AddDeletionFlags(applyCmd)
 
 	// Delete command
 	deleteCmd := BuildDeletionCommand(
@@ -82,26 +221,179 @@ func BuildModelCommands(executeCommand func(commands.Command) error) *cobra.Comm
 		},
 	}
 
+	// Bench command
+	benchCmd := &cobra.Command{
+		Use:   "bench <name...>",
+		Short: "Measure latency and throughput of models",
+		Long: `Send streamed calls to one or more models and report the success rate,
+time to first token, total latency percentiles (p50/p95/p99) and output
+tokens per second. Models are benchmarked one after another.
+
+Tokens per second is estimated from the length of the output, as streamed
+responses do not include token usage. A prompt file may hold several
+prompts separated by lines containing only "---", which are used in turn.`,
+		Example: `  # Benchmark a model with 20 requests, 4 at a time
+  opper models bench mymodel
+
+  # Compare two models with your own prompt and save the results
+  opper models bench mymodel openai/gpt-4o --requests 50 --prompt-file prompt.txt --format json > bench.json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			requests, _ := cmd.Flags().GetInt("requests")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			warmup, _ := cmd.Flags().GetInt("warmup")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			promptFile, _ := cmd.Flags().GetString("prompt-file")
+			format, _ := cmd.Flags().GetString("format")
+			return executeCommand(&commands.BenchModelsCommand{
+				Names:       args,
+				Requests:    requests,
+				Concurrency: concurrency,
+				Warmup:      warmup,
+				Timeout:     timeout,
+				PromptFile:  promptFile,
+				Format:      format,
+			})
+		},
+	}
+	benchCmd.Flags().Int("requests", 20, "Number of measured requests per model")
+	benchCmd.Flags().Int("concurrency", 4, "Number of requests in flight at once")
+	benchCmd.Flags().Int("warmup", 1, "Requests sent before measuring, not counted")
+	benchCmd.Flags().Duration("timeout", 2*time.Minute, "Timeout for each request")
+	benchCmd.Flags().String("prompt-file", "", "Read the prompt from a file ('-' for stdin)")
+	benchCmd.Flags().String("format", "table", "Output format: table or json")
+
+	// Compare command
+	compareCmd := &cobra.Command{
+		Use:   "compare [input]",
+		Short: "Compare the output of several models side by side",
+		Long: `Make the same call with every model and show the outputs side by side with
+the latency, tokens and cost of each call. Models can be built-in or custom.
+
+Use --input-file to run several cases from a JSONL file with one object per
+line, with "input" and optional "id" and "expected" fields. With --judge, a
+model ranks the outputs of each case without seeing the model names, and the
+summary counts how often each model was ranked first.`,
+		Example: `  # Compare three models on a single input
+  opper models compare --models openai/gpt-4o,anthropic/claude-3.5-sonnet,mymodel \
+    --instructions "Summarize in one sentence" "Opper is a platform for building AI features"
+
+  # Run a set of cases and let a judge rank the outputs
+  opper models compare --models a,b --instructions "Answer the question" \
+    --input-file cases.jsonl --judge openai/gpt-4o --criteria "correct and concise"`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			models, _ := cmd.Flags().GetStringSlice("models")
+			name, _ := cmd.Flags().GetString("name")
+			instructions, _ := cmd.Flags().GetString("instructions")
+			inputFile, _ := cmd.Flags().GetString("input-file")
+			judge, _ := cmd.Flags().GetString("judge")
+			criteria, _ := cmd.Flags().GetString("criteria")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			format, _ := cmd.Flags().GetString("format")
+			input := ""
+			if len(args) > 0 {
+				input = args[0]
+			}
+			return executeCommand(&commands.CompareModelsCommand{
+				Models:       models,
+				Name:         name,
+				Instructions: instructions,
+				Input:        input,
+				InputFile:    inputFile,
+				Judge:        judge,
+				Criteria:     criteria,
+				Concurrency:  concurrency,
+				Timeout:      timeout,
+				Format:       format,
+			})
+		},
+	}
+	compareCmd.Flags().StringSlice("models", nil, "Comma-separated models to compare")
+	compareCmd.Flags().String("instructions", "", "Instructions for the call")
+	compareCmd.Flags().String("name", "opper/cli/model-compare", "Function name the calls are recorded under")
+	compareCmd.Flags().String("input-file", "", "JSONL file with one case per line ('-' for stdin)")
+	compareCmd.Flags().String("judge", "", "Model that ranks the outputs of each case")
+	compareCmd.Flags().String("criteria", "", "What the judge should look for")
+	compareCmd.Flags().Int("concurrency", 4, "Number of calls in flight at once")
+	compareCmd.Flags().Duration("timeout", 2*time.Minute, "Timeout for each call")
+	compareCmd.Flags().String("format", "table", "Output format: table or json")
+	compareCmd.MarkFlagRequired("models")
+	compareCmd.MarkFlagRequired("instructions")
+
 	// Builtin command
 	builtinCmd := &cobra.Command{
 		Use:   "builtin [filter]",
 		Short: "List built-in models",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `List the built-in models with their hosting provider, location, context
+window, pricing and capabilities. The optional filter matches part of the
+model name; the flags below narrow the list further.
+
+Prices are in USD per million tokens. Models that do not report a price are
+left out when --max-input-price or --max-output-price is set. Capabilities
+match both listed capabilities such as "tools" and input or output
+modalities such as "image".`,
+		Example: `  # Models hosted in the EU
+  opper models builtin --location eu
+
+  # Cheapest EU models that support tool calls and images
+  opper models builtin --location eu --capability tools --capability image --sort input-cost
+
+  # Models under $1 per million input tokens with at least 100k context, as JSON
+  opper models builtin --max-input-price 1 --min-context 100000 --format json`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			filter := ""
 			if len(args) > 0 {
 				filter = args[0]
 			}
-			return executeCommand(&commands.ListBuiltinModelsCommand{Filter: filter})
+			providers, _ := cmd.Flags().GetStringSlice("provider")
+			locations, _ := cmd.Flags().GetStringSlice("location")
+			capabilities, _ := cmd.Flags().GetStringSlice("capability")
+			maxInputPrice, _ := cmd.Flags().GetFloat64("max-input-price")
+			maxOutputPrice, _ := cmd.Flags().GetFloat64("max-output-price")
+			minContext, _ := cmd.Flags().GetInt("min-context")
+			excludeDeprecated, _ := cmd.Flags().GetBool("exclude-deprecated")
+			sortBy, _ := cmd.Flags().GetString("sort")
+			reverse, _ := cmd.Flags().GetBool("reverse")
+			format, _ := cmd.Flags().GetString("format")
+			return executeCommand(&commands.ListBuiltinModelsCommand{
+				Filter:            filter,
+				Providers:         providers,
+				Locations:         locations,
+				Capabilities:      capabilities,
+				MaxInputPrice:     maxInputPrice,
+				MaxOutputPrice:    maxOutputPrice,
+				MinContext:        minContext,
+				ExcludeDeprecated: excludeDeprecated,
+				Sort:              sortBy,
+				Reverse:           reverse,
+				Format:            format,
+			})
 		},
 	}
+	builtinCmd.Flags().StringSlice("provider", nil, "Only show models from these hosting providers")
+	builtinCmd.Flags().StringSlice("location", nil, "Only show models hosted in these locations, e.g. eu")
+	builtinCmd.Flags().StringSlice("capability", nil, "Only show models with this capability or modality (repeatable)")
+	builtinCmd.Flags().Float64("max-input-price", 0, "Maximum input price in USD per million tokens")
+	builtinCmd.Flags().Float64("max-output-price", 0, "Maximum output price in USD per million tokens")
+	builtinCmd.Flags().Int("min-context", 0, "Minimum context window in tokens")
+	builtinCmd.Flags().Bool("exclude-deprecated", false, "Hide deprecated models")
+	builtinCmd.Flags().String("sort", "name", "Sort by "+strings.Join(opperai.BuiltinSortKeys, ", "))
+	builtinCmd.Flags().Bool("reverse", false, "Reverse the sort order")
+	builtinCmd.Flags().String("format", "table", "Output format: table or json")
 
 	modelsCmd.AddCommand(
 		listCmd,
 		createCmd,
+		updateCmd,
+		applyCmd,
 		deleteCmd,
 		getCmd,
 		testCmd,
+		benchCmd,
+		compareCmd,
 		builtinCmd,
 	)
 
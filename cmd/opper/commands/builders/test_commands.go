@@ -0,0 +1,61 @@
+package builders
+
+import (
+	"github.com/opper-ai/oppercli/cmd/opper/commands"
+	"github.com/spf13/cobra"
+)
+
+func BuildTestCommand(executeCommand func(commands.Command) error) *cobra.Command {
+	testCmd := &cobra.Command{
+		Use:   "test [paths...]",
+		Short: "Run prompt tests",
+		Long: `Run prompt tests found in *.tests.yaml files.
+
+Each test file sits next to the prompt it exercises (greeting.tests.yaml
+tests greeting.prompt). A prompt file holds the instructions, optionally
+preceded by YAML front matter with a name and model. Test cases give an
+input and any of: expected, contains, not_contains, regex, max_length,
+json_schema and snapshot. Snapshots are stored in
+testdata/<prompt>/<case>.golden and refreshed with --update.`,
+		Example: `  # Run all prompt tests below the current directory
+  opper test
+
+  # Run tests in a directory and write a JUnit report for CI
+  opper test prompts/ --format junit --out report.xml
+
+  # Run matching tests against another model
+  opper test --run 'greeting.*formal' --model openai/gpt-4o-mini
+
+  # Refresh golden snapshots
+  opper test --update`,
+		// A failing test is not a usage error
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			outPath, _ := cmd.Flags().GetString("out")
+			update, _ := cmd.Flags().GetBool("update")
+			model, _ := cmd.Flags().GetString("model")
+			run, _ := cmd.Flags().GetString("run")
+			parallel, _ := cmd.Flags().GetInt("parallel")
+
+			return executeCommand(&commands.PromptTestCommand{
+				Paths:    args,
+				Format:   format,
+				OutPath:  outPath,
+				Update:   update,
+				Model:    model,
+				Run:      run,
+				Parallel: parallel,
+			})
+		},
+	}
+
+	testCmd.Flags().String("format", "tap", "Report format (tap, junit)")
+	testCmd.Flags().String("out", "", "Write the report to a file instead of stdout")
+	testCmd.Flags().Bool("update", false, "Write outputs to snapshot files instead of comparing them")
+	testCmd.Flags().String("model", "", "Model to use for every test, overriding prompt and test files")
+	testCmd.Flags().String("run", "", "Only run tests whose '<file>/<name>' matches this regular expression")
+	testCmd.Flags().Int("parallel", 4, "Number of tests to run in parallel")
+
+	return testCmd
+}
@@ -9,9 +9,12 @@ func BuildTraceCommands(executeCommand func(commands.Command) error) *cobra.Comm
 	tracesCmd := &cobra.Command{
 		Use:   "traces",
 		Short: "Manage traces",
-		Example: `  # List all traces
+		Example: `  # List the 100 most recent traces
   opper traces list
 
+  # List every trace
+  opper traces list --all
+
   # Get trace details
   opper traces get <trace-id>
 
@@ -25,10 +28,16 @@ func BuildTraceCommands(executeCommand func(commands.Command) error) *cobra.Comm
 		Short: "List traces",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			live, _ := cmd.Flags().GetBool("live")
-			return executeCommand(&commands.ListTracesCommand{Live: live})
+			limit, pageSize := GetPaginationFlags(cmd)
+			return executeCommand(&commands.ListTracesCommand{
+				Live:     live,
+				Limit:    limit,
+				PageSize: pageSize,
+			})
 		},
 	}
 	AddLiveFlags(listCmd)
+	AddPaginationFlags(listCmd, 100)
 
 	// Get command
 	getCmd := &cobra.Command{
@@ -2,7 +2,10 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/opper-ai/oppercli/opperai"
 )
@@ -20,6 +23,10 @@ func (c *CallCommand) Execute(ctx context.Context, client *opperai.Client) error
 		return fmt.Errorf("instructions are required")
 	}
 
+	if c.Index != "" {
+		return c.callWithIndex(ctx, client)
+	}
+
 	// Try with non-streaming first
 	response, err := client.Call.Call(ctx, c.Name, c.Instructions, c.Input, c.Model, false, c.Tags)
 	if err != nil {
@@ -41,9 +48,54 @@ func (c *CallCommand) Execute(ctx context.Context, client *opperai.Client) error
 			fmt.Print(delta)
 		}
 		fmt.Println()
+		if err := streamResponse.Err(); err != nil {
+			return err
+		}
 	} else {
 		fmt.Println(response.Message)
 	}
 
 	return nil
 }
+
+// callWithIndex answers from index results and lists the cited sources
+func (c *CallCommand) callWithIndex(ctx context.Context, client *opperai.Client) error {
+	filters, err := opperai.ParseFilters(c.Filters)
+	if err != nil {
+		return err
+	}
+
+	response, err := client.Call.CallWithIndex(ctx, c.Name, c.Instructions, c.Input, opperai.RAGOptions{
+		Index:    c.Index,
+		Filters:  filters,
+		TopK:     c.TopK,
+		MinScore: c.MinScore,
+		Model:    c.Model,
+		Tags:     c.Tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(c.Format) {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(response)
+	case "", "text":
+	default:
+		return fmt.Errorf("unknown format: %s (must be text or json)", c.Format)
+	}
+
+	fmt.Println(response.Message)
+	if len(response.Sources) == 0 {
+		fmt.Fprintf(os.Stderr, "\nNo sources found in index '%s'\n", c.Index)
+		return nil
+	}
+
+	fmt.Println("\nSources:")
+	for _, s := range response.Cited() {
+		fmt.Printf("  [%d] %s (score %.3f)\n", s.ID, s.Key, s.Score)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+// datasetForFunction resolves the dataset UUID attached to a function
+func datasetForFunction(ctx context.Context, client *opperai.Client, functionPath string) (string, error) {
+	function, err := client.Functions.GetByPath(ctx, functionPath)
+	if err != nil {
+		return "", fmt.Errorf("error retrieving function: %w", err)
+	}
+	if function.Dataset.UUID == "" {
+		return "", fmt.Errorf("function %s has no dataset", functionPath)
+	}
+	return function.Dataset.UUID, nil
+}
+
+func (c *ListDatasetEntriesCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	datasetUUID, err := datasetForFunction(ctx, client, c.FunctionPath)
+	if err != nil {
+		return err
+	}
+
+	it := client.Datasets.IterEntries(ctx, datasetUUID, &opperai.ListOptions{
+		Limit:    c.Limit,
+		PageSize: c.PageSize,
+	})
+
+	var rows [][]string
+	for it.Next() {
+		entry := it.Value()
+		rows = append(rows, []string{
+			entry.UUID,
+			truncateString(strings.ReplaceAll(entry.Input, "\n", " "), 40),
+			truncateString(strings.ReplaceAll(entry.Output, "\n", " "), 40),
+			truncateString(strings.ReplaceAll(entry.Comment, "\n", " "), 30),
+		})
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("error listing dataset entries: %w", err)
+	}
+
+	output.Table(
+		[]string{"UUID", "INPUT", "EXPECTED", "COMMENT"},
+		rows,
+	)
+
+	if total := it.Total(); len(rows) < total {
+		fmt.Fprintf(os.Stderr, "\nShowing %d of %d entries, use --all to list everything\n", len(rows), total)
+	}
+	return nil
+}
+
+func (c *GetDatasetEntryCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	datasetUUID, err := datasetForFunction(ctx, client, c.FunctionPath)
+	if err != nil {
+		return err
+	}
+
+	entry, err := client.Datasets.GetEntry(ctx, datasetUUID, c.EntryUUID)
+	if err != nil {
+		return fmt.Errorf("error retrieving dataset entry: %w", err)
+	}
+
+	fmt.Printf("Entry: %s\n", entry.UUID)
+	if entry.CreatedAt != "" {
+		fmt.Printf("Created: %s\n", entry.CreatedAt)
+	}
+	fmt.Printf("\nInput:\n%s\n", entry.Input)
+	fmt.Printf("\nExpected:\n%s\n", entry.Output)
+	if entry.Comment != "" {
+		fmt.Printf("\nComment:\n%s\n", entry.Comment)
+	}
+	return nil
+}
+
+func (c *AddDatasetEntryCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	if c.Input == "" {
+		return fmt.Errorf("input required")
+	}
+
+	datasetUUID, err := datasetForFunction(ctx, client, c.FunctionPath)
+	if err != nil {
+		return err
+	}
+
+	entry, err := client.Datasets.AddEntry(ctx, datasetUUID, opperai.DatasetEntry{
+		Input:   c.Input,
+		Output:  c.Expected,
+		Comment: c.Comment,
+	})
+	if err != nil {
+		return fmt.Errorf("error adding dataset entry: %w", err)
+	}
+
+	fmt.Printf("Added dataset entry %s\n", entry.UUID)
+	return nil
+}
+
+func (c *UpdateDatasetEntryCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	if c.Input == nil && c.Expected == nil && c.Comment == nil {
+		return fmt.Errorf("nothing to update, specify at least one of --input, --expected or --comment")
+	}
+
+	datasetUUID, err := datasetForFunction(ctx, client, c.FunctionPath)
+	if err != nil {
+		return err
+	}
+
+	entry, err := client.Datasets.GetEntry(ctx, datasetUUID, c.EntryUUID)
+	if err != nil {
+		return fmt.Errorf("error retrieving dataset entry: %w", err)
+	}
+
+	if c.Input != nil {
+		entry.Input = *c.Input
+	}
+	if c.Expected != nil {
+		entry.Output = *c.Expected
+	}
+	if c.Comment != nil {
+		entry.Comment = *c.Comment
+	}
+
+	if _, err := client.Datasets.UpdateEntry(ctx, datasetUUID, c.EntryUUID, *entry); err != nil {
+		return fmt.Errorf("error updating dataset entry: %w", err)
+	}
+
+	fmt.Printf("Updated dataset entry %s\n", c.EntryUUID)
+	return nil
+}
+
+func (c *DeleteDatasetEntryCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	datasetUUID, err := datasetForFunction(ctx, client, c.FunctionPath)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Datasets.DeleteEntry(ctx, datasetUUID, c.EntryUUID); err != nil {
+		return fmt.Errorf("error deleting dataset entry: %w", err)
+	}
+
+	fmt.Printf("Deleted dataset entry %s\n", c.EntryUUID)
+	return nil
+}
+
+func (c *ImportDatasetCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	format := c.Format
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(c.FilePath)), ".")
+	}
+
+	var r io.Reader = os.Stdin
+	if c.FilePath != "-" {
+		file, err := os.Open(c.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to open file: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	entries, err := opperai.ReadDatasetEntries(r, format)
+	if err != nil {
+		return fmt.Errorf("error reading dataset file: %w", err)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no entries found in %s", c.FilePath)
+	}
+
+	datasetUUID, err := datasetForFunction(ctx, client, c.FunctionPath)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for i, entry := range entries {
+		if _, err := client.Datasets.AddEntry(ctx, datasetUUID, entry); err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "\nentry %d: %v\n", i+1, err)
+		}
+		fmt.Fprintf(os.Stderr, "\rImporting entries... %d/%d", i+1, len(entries))
+	}
+	fmt.Fprintln(os.Stderr)
+
+	fmt.Printf("Imported %d of %d entries into dataset for %s\n", len(entries)-failed, len(entries), c.FunctionPath)
+	if failed > 0 {
+		return fmt.Errorf("%d entries failed to import", failed)
+	}
+	return nil
+}
+
+func (c *ExportDatasetCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	datasetUUID, err := datasetForFunction(ctx, client, c.FunctionPath)
+	if err != nil {
+		return err
+	}
+
+	entries, err := client.Datasets.IterEntries(ctx, datasetUUID, nil).All()
+	if err != nil {
+		return fmt.Errorf("error listing dataset entries: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if c.OutPath != "" && c.OutPath != "-" {
+		file, err := os.Create(c.OutPath)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := opperai.WriteDatasetEntriesJSONL(w, entries); err != nil {
+		return fmt.Errorf("error writing dataset: %w", err)
+	}
+
+	if w != os.Stdout {
+		fmt.Printf("Exported %d entries to %s\n", len(entries), c.OutPath)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package commands
+
+import (
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+//go:embed templates/evaluation_report.html
+var evaluationReportTemplate string
+
+const histogramBins = 10
+
+func (c *ExportEvaluationCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	format := strings.ToLower(c.Format)
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "jsonl" && format != "html" {
+		return fmt.Errorf("unknown format: %s (must be csv, jsonl or html)", c.Format)
+	}
+
+	function, err := client.Functions.GetByPath(ctx, c.FunctionPath)
+	if err != nil {
+		return fmt.Errorf("error retrieving function: %w", err)
+	}
+	if err := checkEvaluationsBelong(ctx, client, function, c.EvaluationUUID); err != nil {
+		return err
+	}
+
+	eval, err := client.Functions.GetEvaluation(ctx, c.EvaluationUUID)
+	if err != nil {
+		return fmt.Errorf("error retrieving evaluation: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if c.OutPath != "" && c.OutPath != "-" {
+		file, err := os.Create(c.OutPath)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch format {
+	case "jsonl":
+		err = writeEvaluationJSONL(w, eval)
+	case "html":
+		err = writeEvaluationHTML(w, c.FunctionPath, eval)
+	default:
+		err = writeEvaluationCSV(w, eval)
+	}
+	if err != nil {
+		return fmt.Errorf("error exporting evaluation: %w", err)
+	}
+
+	if w != os.Stdout {
+		fmt.Printf("Exported %d records to %s\n", len(eval.Records), c.OutPath)
+	}
+	return nil
+}
+
+// evaluationDimensions returns the dimensions of an evaluation, falling back
+// to the metrics found on the records when the evaluation does not list them
+func evaluationDimensions(eval *opperai.Evaluation) []string {
+	if len(eval.Dimensions) > 0 {
+		return eval.Dimensions
+	}
+
+	seen := map[string]bool{}
+	var dims []string
+	for _, record := range eval.Records {
+		for dim := range record.Metrics {
+			if !seen[dim] {
+				seen[dim] = true
+				dims = append(dims, dim)
+			}
+		}
+	}
+	sort.Strings(dims)
+	return dims
+}
+
+func writeEvaluationCSV(w io.Writer, eval *opperai.Evaluation) error {
+	cw := csv.NewWriter(w)
+	dims := evaluationDimensions(eval)
+
+	headers := []string{"record_uuid", "dataset_entry_uuid", "status", "input", "expected", "output"}
+	for _, dim := range dims {
+		headers = append(headers, dim, dim+".comment")
+	}
+	if err := cw.Write(headers); err != nil {
+		return fmt.Errorf("error writing CSV header: %v", err)
+	}
+
+	for _, record := range eval.Records {
+		row := []string{
+			record.EvaluationRecordUUID,
+			record.DatasetEntryUUID,
+			record.Status.State,
+			record.Input,
+			record.Expected,
+			record.Output,
+		}
+		for _, dim := range dims {
+			if metric, ok := record.Metrics[dim]; ok {
+				row = append(row, strconv.FormatFloat(metric.Value, 'f', -1, 64), metric.Comment)
+			} else {
+				row = append(row, "", "")
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("error writing CSV row: %v", err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeEvaluationJSONL(w io.Writer, eval *opperai.Evaluation) error {
+	enc := json.NewEncoder(w)
+	for _, record := range eval.Records {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type reportSummary struct {
+	Dimension string
+	Stats     opperai.StatisticsSummary
+}
+
+type reportBin struct {
+	Low, High float64
+	Count     int
+	Height    float64
+}
+
+type reportHistogram struct {
+	Dimension string
+	Min, Max  float64
+	Bins      []reportBin
+}
+
+type reportMetric struct {
+	Present bool
+	Value   float64
+	Comment string
+}
+
+type reportRecord struct {
+	Status, Input, Expected, Output string
+	Metrics                         []reportMetric
+}
+
+func writeEvaluationHTML(w io.Writer, functionPath string, eval *opperai.Evaluation) error {
+	tmpl, err := template.New("report").Parse(evaluationReportTemplate)
+	if err != nil {
+		return err
+	}
+
+	dims := evaluationDimensions(eval)
+	data := struct {
+		FunctionPath string
+		Evaluation   *opperai.Evaluation
+		Dimensions   []string
+		Summary      []reportSummary
+		Histograms   []reportHistogram
+		Records      []reportRecord
+	}{
+		FunctionPath: functionPath,
+		Evaluation:   eval,
+		Dimensions:   dims,
+	}
+
+	for _, dim := range dims {
+		var values []float64
+		for _, record := range eval.Records {
+			if metric, ok := record.Metrics[dim]; ok {
+				values = append(values, metric.Value)
+			}
+		}
+
+		stats, ok := eval.SummaryStatistics[dim]
+		if !ok {
+			stats = opperai.Summarize(values)
+		}
+		data.Summary = append(data.Summary, reportSummary{Dimension: dim, Stats: stats})
+
+		if len(values) > 0 {
+			data.Histograms = append(data.Histograms, histogram(dim, values))
+		}
+	}
+
+	for _, record := range eval.Records {
+		row := reportRecord{
+			Status:   record.Status.State,
+			Input:    record.Input,
+			Expected: record.Expected,
+			Output:   record.Output,
+		}
+		for _, dim := range dims {
+			metric, ok := record.Metrics[dim]
+			row.Metrics = append(row.Metrics, reportMetric{
+				Present: ok,
+				Value:   metric.Value,
+				Comment: metric.Comment,
+			})
+		}
+		data.Records = append(data.Records, row)
+	}
+
+	return tmpl.Execute(w, data)
+}
+
+// histogram buckets values into equal-width bins. Scores within [0, 1] use
+// that range so that histograms of different runs line up.
+func histogram(dim string, values []float64) reportHistogram {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if lo >= 0 && hi <= 1 {
+		lo, hi = 0, 1
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+
+	width := (hi - lo) / histogramBins
+	bins := make([]reportBin, histogramBins)
+	for i := range bins {
+		bins[i].Low = lo + float64(i)*width
+		bins[i].High = bins[i].Low + width
+	}
+	for _, v := range values {
+		i := int((v - lo) / width)
+		if i >= histogramBins {
+			i = histogramBins - 1
+		}
+		bins[i].Count++
+	}
+
+	var peak int
+	for _, bin := range bins {
+		if bin.Count > peak {
+			peak = bin.Count
+		}
+	}
+	for i := range bins {
+		bins[i].Height = 100 * float64(bins[i].Count) / float64(peak)
+	}
+
+	return reportHistogram{Dimension: dim, Min: lo, Max: hi, Bins: bins}
+}
@@ -3,9 +3,15 @@ package commands
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
 	"github.com/opper-ai/oppercli/opperai"
@@ -20,31 +26,172 @@ func (c *DeleteCommand) Execute(ctx context.Context, client *opperai.Client) err
 	return nil
 }
 
+// functionColumns maps column names accepted by --columns to their header and value
+var functionColumns = map[string]struct {
+	header string
+	value  func(f opperai.FunctionDescription) string
+}{
+	"path":        {"PATH", func(f opperai.FunctionDescription) string { return f.Path }},
+	"description": {"DESCRIPTION", func(f opperai.FunctionDescription) string { return truncateString(f.Description, 50) }},
+	"model":       {"MODEL", func(f opperai.FunctionDescription) string { return f.Model }},
+	"revision":    {"REVISION", func(f opperai.FunctionDescription) string { return strconv.Itoa(f.Revision) }},
+	"dataset":     {"DATASET", func(f opperai.FunctionDescription) string { return strconv.Itoa(f.Dataset.EntryCount) }},
+	"uuid":        {"UUID", func(f opperai.FunctionDescription) string { return f.UUID }},
+	"project":     {"PROJECT", func(f opperai.FunctionDescription) string { return f.Project.Name }},
+	"updated":     {"UPDATED", func(f opperai.FunctionDescription) string { return f.UpdatedAt }},
+}
+
+// functionSortFields maps values accepted by --sort to API field names
+var functionSortFields = map[string]string{
+	"path":     "path",
+	"revision": "revision",
+	"updated":  "updated_at",
+}
+
 func (c *ListCommand) Execute(ctx context.Context, client *opperai.Client) error {
-	functions, err := client.Functions.List(ctx)
-	if err != nil {
+	columns := c.Columns
+	if len(columns) == 0 {
+		columns = []string{"path", "model", "revision", "dataset", "description"}
+	}
+	headers := make([]string, len(columns))
+	for i, name := range columns {
+		col, ok := functionColumns[name]
+		if !ok {
+			return fmt.Errorf("unknown column: %s", name)
+		}
+		headers[i] = col.header
+	}
+
+	var pathRegex *regexp.Regexp
+	if c.PathRegex != "" {
+		var err error
+		pathRegex, err = regexp.Compile(c.PathRegex)
+		if err != nil {
+			return fmt.Errorf("invalid path regex: %w", err)
+		}
+	}
+	if c.PathGlob != "" {
+		if _, err := path.Match(c.PathGlob, ""); err != nil {
+			return fmt.Errorf("invalid path glob: %w", err)
+		}
+	}
+
+	metadata := make(map[string]string, len(c.Metadata))
+	for _, pair := range c.Metadata {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid metadata filter %q, expected key=value", pair)
+		}
+		metadata[key] = value
+	}
+
+	sortField, descending := strings.CutPrefix(c.Sort, "-")
+	apiSort := ""
+	if sortField != "" {
+		field, ok := functionSortFields[sortField]
+		if !ok {
+			return fmt.Errorf("unknown sort field: %s (must be path, revision or updated)", sortField)
+		}
+		apiSort = field
+		if descending {
+			apiSort = "-" + field
+		}
+	}
+
+	params := &opperai.FunctionListParams{
+		Project:    c.Project,
+		Model:      c.Model,
+		HasDataset: c.HasDataset,
+		Metadata:   c.Metadata,
+		Sort:       apiSort,
+	}
+	// The limit is applied after filtering, so the iterator fetches pages
+	// until enough functions match.
+	it := client.Functions.Iter(ctx, params, &opperai.ListOptions{
+		PageSize: c.PageSize,
+	})
+
+	// The server applies the same filters, they are repeated here so that
+	// the output is correct against API versions that ignore some of them.
+	var functions []opperai.FunctionDescription
+	truncated := false
+	for it.Next() {
+		function := it.Value()
+		if c.Filter != "" && !strings.Contains(function.Path, c.Filter) {
+			continue
+		}
+		if c.PathGlob != "" {
+			if ok, _ := path.Match(c.PathGlob, function.Path); !ok {
+				continue
+			}
+		}
+		if pathRegex != nil && !pathRegex.MatchString(function.Path) {
+			continue
+		}
+		if c.Project != "" && function.Project.Name != c.Project && function.Project.UUID != c.Project {
+			continue
+		}
+		if c.Model != "" && function.Model != c.Model {
+			continue
+		}
+		if c.HasDataset && function.Dataset.EntryCount == 0 {
+			continue
+		}
+		if !matchesMetadata(function.Metadata, metadata) {
+			continue
+		}
+		if c.Limit > 0 && len(functions) == c.Limit {
+			truncated = true
+			break
+		}
+		functions = append(functions, function)
+	}
+	if err := it.Err(); err != nil {
 		return fmt.Errorf("error listing functions: %w", err)
 	}
 
-	// Convert data to rows
+	if sortField != "" {
+		sort.SliceStable(functions, func(i, j int) bool {
+			a, b := functions[i], functions[j]
+			if descending {
+				a, b = b, a
+			}
+			switch sortField {
+			case "revision":
+				return a.Revision < b.Revision
+			case "updated":
+				return a.UpdatedAt < b.UpdatedAt
+			default:
+				return a.Path < b.Path
+			}
+		})
+	}
+
 	rows := make([][]string, len(functions))
 	for i, function := range functions {
-		if c.Filter == "" || strings.Contains(function.Path, c.Filter) {
-			rows[i] = []string{
-				function.Path,
-				function.Description,
-			}
+		rows[i] = make([]string, len(columns))
+		for j, name := range columns {
+			rows[i][j] = functionColumns[name].value(function)
 		}
 	}
 
-	// Use the formatter
-	output.Table(
-		[]string{"PATH", "DESCRIPTION"},
-		rows,
-	)
+	output.Table(headers, rows)
+
+	if truncated {
+		fmt.Fprintf(os.Stderr, "\nShowing the first %d matching functions, use --all to list everything\n", len(functions))
+	}
 	return nil
 }
 
+func matchesMetadata(metadata map[string]string, want map[string]string) bool {
+	for key, value := range want {
+		if metadata[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *GetCommand) Execute(ctx context.Context, client *opperai.Client) error {
 	function, err := client.Functions.GetByPath(ctx, c.FunctionPath)
 	if err != nil {
@@ -156,30 +303,26 @@ func (c *ListEvaluationsCommand) Execute(ctx context.Context, client *opperai.Cl
 		return fmt.Errorf("error retrieving function: %w", err)
 	}
 
-	evaluations, err := client.Functions.ListEvaluations(ctx, function.UUID, c.Limit)
+	it := client.Functions.IterEvaluations(ctx, function.UUID, &opperai.ListOptions{
+		Limit:    c.Limit,
+		PageSize: c.PageSize,
+	})
+	evaluations, err := it.All()
 	if err != nil {
 		return fmt.Errorf("error listing evaluations: %w", err)
 	}
 
-	fmt.Printf("Found %d evaluations for function %s\n\n", evaluations.Meta.TotalCount, c.FunctionPath)
+	fmt.Printf("Found %d evaluations for function %s\n\n", it.Total(), c.FunctionPath)
 
 	// Reverse the order of evaluations
-	for i := len(evaluations.Data) - 1; i >= 0; i-- {
-		eval := evaluations.Data[i]
+	for i := len(evaluations) - 1; i >= 0; i-- {
+		eval := evaluations[i]
 		fmt.Printf("Evaluation %s (Created: %s)\n", eval.EvaluationUUID, eval.CreatedAt)
 		fmt.Printf("Status: %s\n", eval.Status.State)
 		fmt.Printf("Model: %s\n", eval.FunctionOverride.Model)
 
 		fmt.Printf("\nSummary Statistics:\n")
-		fmt.Printf("%-20s %10s %10s %10s %10s\n", "Metric", "Min", "Max", "Avg", "Median")
-		fmt.Printf("%s\n", strings.Repeat("-", 70))
-
-		for _, dim := range eval.Dimensions {
-			if stats, ok := eval.SummaryStatistics[dim]; ok {
-				fmt.Printf("%-20s %10.2f %10.2f %10.2f %10.2f\n",
-					dim, stats.Min, stats.Max, stats.Avg, stats.Median)
-			}
-		}
+		printSummaryStatistics(&eval)
 
 		fmt.Printf("\nEvaluation Records:\n")
 		divider := strings.Repeat("-", 100)
@@ -219,17 +362,292 @@ func (c *RunEvaluationCommand) Execute(ctx context.Context, client *opperai.Clie
 		return fmt.Errorf("function has no dataset")
 	}
 
+	override := &opperai.FunctionOverride{
+		Model:        c.Model,
+		FewShotCount: c.FewShotCount,
+	}
+	if c.InstructionsFile != "" {
+		instructions, err := os.ReadFile(c.InstructionsFile)
+		if err != nil {
+			return fmt.Errorf("error reading instructions file: %w", err)
+		}
+		override.Instructions = strings.TrimSpace(string(instructions))
+		if override.Instructions == "" {
+			return fmt.Errorf("instructions file %s is empty", c.InstructionsFile)
+		}
+	}
+
 	fmt.Printf("Running evaluation for function %s using dataset %s...\n", c.FunctionPath, function.Dataset.UUID)
+	if override.Model != "" {
+		fmt.Printf("  model override: %s (function uses %s)\n", override.Model, function.Model)
+	}
+	if override.Instructions != "" {
+		fmt.Printf("  instructions override: %s\n", c.InstructionsFile)
+	}
+	if override.FewShotCount != nil {
+		fmt.Printf("  few-shot override: %d (function uses %d)\n", *override.FewShotCount, function.FewShotCount)
+	}
 
-	err = client.Functions.CreateEvaluation(ctx, function.Dataset.UUID)
+	eval, err := client.Functions.CreateEvaluation(ctx, function.Dataset.UUID, override)
 	if err != nil {
 		return fmt.Errorf("error creating evaluation: %w", err)
 	}
 
-	fmt.Printf("Evaluation started successfully\n")
+	fmt.Printf("Evaluation %s started\n", eval.EvaluationUUID)
+
+	if !c.Wait && len(c.MinAvg) == 0 && len(c.MinMedian) == 0 {
+		return nil
+	}
+
+	waitCtx := ctx
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	interval := c.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	eval, err = client.Functions.WaitForEvaluation(waitCtx, eval.EvaluationUUID, interval, func(e *opperai.Evaluation) {
+		done, total := evaluationProgress(e)
+		fmt.Fprintf(os.Stderr, "\rEvaluating... %d/%d records (%s)", done, total, e.Status.State)
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return fmt.Errorf("error waiting for evaluation: %w", err)
+	}
+
+	if eval.Status.Failed() {
+		if eval.Status.Details != "" {
+			return fmt.Errorf("evaluation %s: %s", eval.Status.State, eval.Status.Details)
+		}
+		return fmt.Errorf("evaluation %s", eval.Status.State)
+	}
+
+	fmt.Printf("\nSummary Statistics:\n")
+	printSummaryStatistics(eval)
+
+	if failures := checkThresholds(eval, c.MinAvg, c.MinMedian); len(failures) > 0 {
+		fmt.Println()
+		for _, failure := range failures {
+			fmt.Printf("FAIL %s\n", failure)
+		}
+		return fmt.Errorf("evaluation below threshold for %d metric(s)", len(failures))
+	}
+
+	return nil
+}
+
+func (c *CompareEvaluationsCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	function, err := client.Functions.GetByPath(ctx, c.FunctionPath)
+	if err != nil {
+		return fmt.Errorf("error retrieving function: %w", err)
+	}
+
+	// Make sure both runs belong to the function before comparing them
+	if err := checkEvaluationsBelong(ctx, client, function, c.EvaluationA, c.EvaluationB); err != nil {
+		return err
+	}
+
+	evalA, err := client.Functions.GetEvaluation(ctx, c.EvaluationA)
+	if err != nil {
+		return fmt.Errorf("error retrieving evaluation: %w", err)
+	}
+	evalB, err := client.Functions.GetEvaluation(ctx, c.EvaluationB)
+	if err != nil {
+		return fmt.Errorf("error retrieving evaluation: %w", err)
+	}
+
+	result := opperai.CompareEvaluations(evalA, evalB, &opperai.CompareOptions{
+		Resamples: c.Resamples,
+	})
+	if c.MaxRegressions >= 0 && len(result.Regressions) > c.MaxRegressions {
+		result.Regressions = result.Regressions[:c.MaxRegressions]
+	}
+
+	switch strings.ToLower(c.Format) {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	case "markdown", "md":
+		printComparisonMarkdown(c.FunctionPath, result)
+	case "", "table":
+		printComparisonTable(result)
+	default:
+		return fmt.Errorf("unknown format: %s (must be table, json or markdown)", c.Format)
+	}
+	return nil
+}
+
+// checkEvaluationsBelong returns an error unless every evaluation UUID is one
+// of the function's evaluations. Evaluations do not record their function,
+// so the function's evaluations are listed.
+func checkEvaluationsBelong(ctx context.Context, client *opperai.Client, function *opperai.FunctionDescription, evaluationUUIDs ...string) error {
+	owned := make(map[string]bool, len(evaluationUUIDs))
+	for _, uuid := range evaluationUUIDs {
+		owned[uuid] = false
+	}
+	it := client.Functions.IterEvaluations(ctx, function.UUID, nil)
+	for it.Next() {
+		if _, ok := owned[it.Value().EvaluationUUID]; ok {
+			owned[it.Value().EvaluationUUID] = true
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("error listing evaluations: %w", err)
+	}
+	for _, uuid := range evaluationUUIDs {
+		if !owned[uuid] {
+			return fmt.Errorf("evaluation %s does not belong to function %s", uuid, function.Path)
+		}
+	}
 	return nil
 }
 
+func printComparisonTable(result *opperai.EvaluationComparison) {
+	fmt.Printf("A: %s\nB: %s\n", result.EvaluationA, result.EvaluationB)
+	fmt.Printf("Matched records: %d (only in A: %d, only in B: %d)\n\n", result.Matched, result.OnlyInA, result.OnlyInB)
+
+	rows := make([][]string, len(result.Dimensions))
+	for i, dim := range result.Dimensions {
+		ci, p := intervalCells(dim)
+		rows[i] = []string{
+			dim.Dimension,
+			fmt.Sprintf("%.3f", dim.MeanA),
+			fmt.Sprintf("%.3f", dim.MeanB),
+			fmt.Sprintf("%+.3f", dim.Delta),
+			ci,
+			p,
+			fmt.Sprintf("%d/%d/%d", dim.Wins, dim.Losses, dim.Ties),
+			significanceLabel(dim),
+		}
+	}
+	output.Table(
+		[]string{"DIMENSION", "A", "B", "DELTA", fmt.Sprintf("%.0f%% CI", result.Confidence*100), "P", "W/L/T", "RESULT"},
+		rows,
+	)
+
+	if len(result.Regressions) > 0 {
+		fmt.Printf("\nRegressions:\n")
+		rows := make([][]string, len(result.Regressions))
+		for i, r := range result.Regressions {
+			rows[i] = []string{
+				r.DatasetEntryUUID,
+				r.Dimension,
+				fmt.Sprintf("%.3f", r.ValueA),
+				fmt.Sprintf("%.3f", r.ValueB),
+				truncateString(strings.ReplaceAll(r.Input, "\n", " "), 50),
+			}
+		}
+		output.Table([]string{"ENTRY", "DIMENSION", "A", "B", "INPUT"}, rows)
+	}
+}
+
+func printComparisonMarkdown(functionPath string, result *opperai.EvaluationComparison) {
+	fmt.Printf("### Evaluation comparison for `%s`\n\n", functionPath)
+	fmt.Printf("- A: `%s`\n- B: `%s`\n", result.EvaluationA, result.EvaluationB)
+	fmt.Printf("- Matched records: %d (only in A: %d, only in B: %d)\n\n", result.Matched, result.OnlyInA, result.OnlyInB)
+
+	fmt.Printf("| Dimension | A | B | Delta | %.0f%% CI | p | W/L/T | Result |\n", result.Confidence*100)
+	fmt.Println("|---|---:|---:|---:|---|---:|---|---|")
+	for _, dim := range result.Dimensions {
+		ci, p := intervalCells(dim)
+		fmt.Printf("| %s | %.3f | %.3f | %+.3f | %s | %s | %d/%d/%d | %s |\n",
+			dim.Dimension, dim.MeanA, dim.MeanB, dim.Delta, ci, p,
+			dim.Wins, dim.Losses, dim.Ties, significanceLabel(dim))
+	}
+
+	if len(result.Regressions) > 0 {
+		fmt.Printf("\n<details><summary>Regressions (%d)</summary>\n\n", len(result.Regressions))
+		fmt.Println("| Entry | Dimension | A | B | Input |")
+		fmt.Println("|---|---|---:|---:|---|")
+		for _, r := range result.Regressions {
+			input := strings.ReplaceAll(truncateString(strings.ReplaceAll(r.Input, "\n", " "), 80), "|", "\\|")
+			fmt.Printf("| `%s` | %s | %.3f | %.3f | %s |\n", r.DatasetEntryUUID, r.Dimension, r.ValueA, r.ValueB, input)
+		}
+		fmt.Println("\n</details>")
+	}
+}
+
+// intervalCells formats the confidence interval and p-value, or "-" when
+// they were not computed
+func intervalCells(dim opperai.DimensionComparison) (string, string) {
+	if dim.Pairs == 0 || dim.Insufficient {
+		return "-", "-"
+	}
+	return fmt.Sprintf("[%+.3f, %+.3f]", dim.CILow, dim.CIHigh), fmt.Sprintf("%.4f", dim.PValue)
+}
+
+func significanceLabel(dim opperai.DimensionComparison) string {
+	switch {
+	case dim.Pairs == 0:
+		return "no data"
+	case dim.Insufficient:
+		return fmt.Sprintf("insufficient data (%d pairs, need %d)", dim.Pairs, opperai.MinSignificancePairs)
+	case !dim.Significant:
+		return "no significant change"
+	case dim.Delta > 0:
+		return "improved"
+	default:
+		return "regressed"
+	}
+}
+
+func printSummaryStatistics(eval *opperai.Evaluation) {
+	fmt.Printf("%-20s %10s %10s %10s %10s\n", "Metric", "Min", "Max", "Avg", "Median")
+	fmt.Printf("%s\n", strings.Repeat("-", 70))
+
+	for _, dim := range eval.Dimensions {
+		if stats, ok := eval.SummaryStatistics[dim]; ok {
+			fmt.Printf("%-20s %10.2f %10.2f %10.2f %10.2f\n",
+				dim, stats.Min, stats.Max, stats.Avg, stats.Median)
+		}
+	}
+}
+
+// evaluationProgress returns the number of finished records and the total number of records
+func evaluationProgress(eval *opperai.Evaluation) (int, int) {
+	var done int
+	for _, record := range eval.Records {
+		if record.Status.Done() {
+			done++
+		}
+	}
+	return done, len(eval.Records)
+}
+
+// checkThresholds returns a description of every dimension whose average or
+// median is below the requested minimum
+func checkThresholds(eval *opperai.Evaluation, minAvg, minMedian map[string]float64) []string {
+	var failures []string
+	check := func(kind string, thresholds map[string]float64, value func(opperai.StatisticsSummary) float64) {
+		dims := make([]string, 0, len(thresholds))
+		for dim := range thresholds {
+			dims = append(dims, dim)
+		}
+		sort.Strings(dims)
+
+		for _, dim := range dims {
+			stats, ok := eval.SummaryStatistics[dim]
+			if !ok {
+				failures = append(failures, fmt.Sprintf("%s: no statistics reported", dim))
+				continue
+			}
+			if v := value(stats); v < thresholds[dim] {
+				failures = append(failures, fmt.Sprintf("%s: %s %.4f < %.4f", dim, kind, v, thresholds[dim]))
+			}
+		}
+	}
+
+	check("average", minAvg, func(s opperai.StatisticsSummary) float64 { return s.Avg })
+	check("median", minMedian, func(s opperai.StatisticsSummary) float64 { return s.Median })
+	return failures
+}
+
 func ParseFunctionCommand(args []string) (Command, error) {
 	if len(args) < 1 {
 		return nil, fmt.Errorf("function subcommand required (list, create, delete, get, chat)")
@@ -318,3 +736,120 @@ func ParseFunctionCommand(args []string) (Command, error) {
 		return nil, fmt.Errorf("unknown function subcommand: %s", subcommand)
 	}
 }
+
+func (c *FunctionHistoryCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	function, err := client.Functions.GetByPath(ctx, c.FunctionPath)
+	if err != nil {
+		return fmt.Errorf("error retrieving function: %w", err)
+	}
+
+	revisions, err := client.Functions.ListRevisions(ctx, function.UUID)
+	if err != nil {
+		return fmt.Errorf("error listing revisions: %w", err)
+	}
+
+	rows := make([][]string, 0, len(revisions))
+	for i := len(revisions) - 1; i >= 0; i-- {
+		rev := revisions[i]
+		current := ""
+		if rev.Revision == function.Revision {
+			current = "*"
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d%s", rev.Revision, current),
+			rev.CreatedAt,
+			rev.Model,
+			truncateString(strings.ReplaceAll(rev.Instructions, "\n", " "), 60),
+		})
+	}
+
+	output.Table(
+		[]string{"REVISION", "CREATED", "MODEL", "INSTRUCTIONS"},
+		rows,
+	)
+	return nil
+}
+
+func (c *FunctionDiffCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	function, err := client.Functions.GetByPath(ctx, c.FunctionPath)
+	if err != nil {
+		return fmt.Errorf("error retrieving function: %w", err)
+	}
+
+	to := c.To
+	if to == 0 {
+		to = function.Revision
+	}
+	from := c.From
+	if from == 0 {
+		from = to - 1
+	}
+	if from < 1 {
+		return fmt.Errorf("function %s has no earlier revision to compare with", c.FunctionPath)
+	}
+
+	fromRev, err := client.Functions.GetRevision(ctx, function.UUID, from)
+	if err != nil {
+		return fmt.Errorf("error retrieving revision: %w", err)
+	}
+	toRev, err := client.Functions.GetRevision(ctx, function.UUID, to)
+	if err != nil {
+		return fmt.Errorf("error retrieving revision: %w", err)
+	}
+
+	diff := output.UnifiedDiff(
+		fmt.Sprintf("%s@%d", c.FunctionPath, from),
+		fmt.Sprintf("%s@%d", c.FunctionPath, to),
+		renderRevision(fromRev),
+		renderRevision(toRev),
+	)
+	if diff == "" {
+		fmt.Printf("No changes between revision %d and %d\n", from, to)
+		return nil
+	}
+
+	fmt.Print(diff)
+	return nil
+}
+
+func (c *FunctionRollbackCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	function, err := client.Functions.GetByPath(ctx, c.FunctionPath)
+	if err != nil {
+		return fmt.Errorf("error retrieving function: %w", err)
+	}
+
+	if c.To == function.Revision {
+		fmt.Printf("Function %s is already at revision %d\n", c.FunctionPath, c.To)
+		return nil
+	}
+
+	updated, err := client.Functions.Rollback(ctx, function.UUID, c.To)
+	if err != nil {
+		return fmt.Errorf("error rolling back function: %w", err)
+	}
+
+	fmt.Printf("Rolled back %s to revision %d (new revision: %d)\n", c.FunctionPath, c.To, updated.Revision)
+	return nil
+}
+
+// renderRevision formats the tracked fields of a revision as text suitable for diffing
+func renderRevision(rev *opperai.FunctionRevision) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "model: %s\n", rev.Model)
+	fmt.Fprintf(&sb, "description: %s\n", rev.Description)
+	fmt.Fprintf(&sb, "\ninstructions:\n%s\n", strings.TrimRight(rev.Instructions, "\n"))
+	fmt.Fprintf(&sb, "\ninput_schema:\n%s\n", formatSchema(rev.InputSchema))
+	fmt.Fprintf(&sb, "\noutput_schema:\n%s\n", formatSchema(rev.OutputSchema))
+	return sb.String()
+}
+
+func formatSchema(schema map[string]interface{}) string {
+	if len(schema) == 0 {
+		return "(none)"
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", schema)
+	}
+	return string(data)
+}
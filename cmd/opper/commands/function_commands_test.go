@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+func TestCheckEvaluationsBelong(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/functions/fn-a/evaluations" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var resp opperai.EvaluationsResponse
+		resp.Meta.TotalCount = 2
+		resp.Data = []opperai.Evaluation{{EvaluationUUID: "eval-1"}, {EvaluationUUID: "eval-2"}}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := opperai.NewClient("test-key", server.URL)
+	function := &opperai.FunctionDescription{UUID: "fn-a", Path: "a"}
+
+	tests := []struct {
+		name    string
+		uuids   []string
+		wantErr string
+	}{
+		{name: "own evaluation", uuids: []string{"eval-1"}},
+		{name: "both own evaluations", uuids: []string{"eval-1", "eval-2"}},
+		{name: "evaluation of another function", uuids: []string{"eval-1", "eval-9"}, wantErr: "evaluation eval-9 does not belong to function a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkEvaluationsBelong(context.Background(), client, function, tt.uuids...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkEvaluationsBelong() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkEvaluationsBelong() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"github.com/opper-ai/oppercli/opperai"
+	"github.com/opper-ai/oppercli/opperai/chunking"
+)
+
+// addChunks splits a local file or directory into chunks and adds each chunk
+// to the index as a document
+func (c *AddToIndexCommand) addChunks(ctx context.Context, client *opperai.Client) error {
+	var extra map[string]interface{}
+	if c.Metadata != "" {
+		if err := json.Unmarshal([]byte(c.Metadata), &extra); err != nil {
+			return fmt.Errorf("invalid metadata JSON: %v", err)
+		}
+	}
+	opts := chunking.Options{Size: c.ChunkSize, Overlap: c.ChunkOverlap}
+	if _, err := chunking.New(c.Strategy, "", opts); err != nil {
+		return err
+	}
+
+	files, err := c.chunkSources()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no files found in %s", c.Chunk)
+	}
+
+	var docs []opperai.Document
+	var sources []string
+	counts := map[string]int{}
+	for _, file := range files {
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return err
+		}
+		if !isText(data) {
+			fmt.Fprintf(os.Stderr, "skipping %s: not a text file\n", file.RelPath)
+			continue
+		}
+
+		chunker, _ := chunking.New(c.Strategy, file.Path, opts)
+		chunks := chunker.Chunk(string(data))
+		if len(chunks) == 0 {
+			continue
+		}
+		docs = append(docs, chunking.Documents(file.RelPath, chunks, extra)...)
+		sources = append(sources, file.RelPath)
+		counts[file.RelPath] = len(chunks)
+	}
+
+	if c.DryRun {
+		enc := json.NewEncoder(os.Stdout)
+		for _, doc := range docs {
+			if err := enc.Encode(doc); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Dry run: %d chunks from %d files, nothing added\n", len(docs), len(sources))
+		return nil
+	}
+	if len(docs) == 0 {
+		return fmt.Errorf("no text content found in %s", c.Chunk)
+	}
+
+	if _, err := client.Indexes.Get(ctx, c.Name); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	added, err := addDocumentBatches(client, c.Name, docs, c.Batch)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added %d chunks from %d files to index '%s' in %s", added, len(sources), c.Name, time.Since(start).Round(100*time.Millisecond))
+	if added < len(docs) {
+		fmt.Printf(", %d failed", len(docs)-added)
+	}
+	fmt.Println()
+
+	if added < len(docs) {
+		if c.Replace {
+			fmt.Fprintln(os.Stderr, "Old chunks were kept because not every chunk was added")
+		}
+		return fmt.Errorf("%d of %d chunks failed to add", len(docs)-added, len(docs))
+	}
+
+	if c.Replace {
+		// Chunks with the same key were overwritten by the add, so only
+		// chunks beyond the new count of each file are left over
+		removed := 0
+		for _, source := range sources {
+			n, err := client.Indexes.DeleteDocuments(c.Name, []opperai.Filter{
+				{Field: "source", Operation: opperai.FilterEqual, Value: source},
+				{Field: "chunk_index", Operation: opperai.FilterGreaterOrEqual, Value: counts[source]},
+			})
+			if err != nil {
+				return fmt.Errorf("error removing old chunks of %s: %w", source, err)
+			}
+			removed += n
+		}
+		if removed > 0 {
+			fmt.Printf("Removed %d old chunks\n", removed)
+		}
+	}
+	return nil
+}
+
+// chunkSources returns the files to chunk. Files in a directory are named by
+// their path relative to it, a single file by its base name.
+func (c *AddToIndexCommand) chunkSources() ([]localFile, error) {
+	info, err := os.Stat(c.Chunk)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("file does not exist: %s", c.Chunk)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return collectFiles(c.Chunk, c.Recursive, c.Include, c.Exclude)
+	}
+	return []localFile{{Path: c.Chunk, RelPath: filepath.Base(c.Chunk), Size: info.Size()}}, nil
+}
+
+// isText reports whether data looks like UTF-8 text rather than a binary file
+func isText(data []byte) bool {
+	head := data[:min(len(data), 8000)]
+	return bytes.IndexByte(head, 0) < 0 && utf8.Valid(data)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -67,53 +68,131 @@ func (c *DeleteIndexCommand) Execute(ctx context.Context, client *opperai.Client
 	return nil
 }
 
-func (c *GetIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
-	index, err := client.Indexes.Get(c.Name)
+// queryColumns maps column names accepted by --columns to their header and
+// value. Metadata fields are selected with "metadata.<field>".
+var queryColumns = map[string]struct {
+	header string
+	value  func(r opperai.RetrievalResponse) string
+}{
+	"score":    {"SCORE", func(r opperai.RetrievalResponse) string { return fmt.Sprintf("%.4f", r.Score) }},
+	"key":      {"KEY", func(r opperai.RetrievalResponse) string { return r.Key }},
+	"content":  {"CONTENT", func(r opperai.RetrievalResponse) string { return truncateString(singleLine(r.Content), 80) }},
+	"metadata": {"METADATA", func(r opperai.RetrievalResponse) string { return truncateString(formatMetadataValue(r.Metadata), 60) }},
+}
+
+func (c *QueryIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	filters, err := opperai.ParseFilters(c.Filters)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Index: %s\n", index.Name)
-	fmt.Printf("Created: %s\n", index.CreatedAt.Format(time.RFC3339))
+	format := strings.ToLower(c.Format)
+	switch format {
+	case "", "table", "json", "plain":
+	default:
+		return fmt.Errorf("unknown format: %s (must be table, json or plain)", c.Format)
+	}
 
-	if len(index.Files) > 0 {
-		fmt.Println("\nIndexed Files:")
-		fmt.Printf("%-50s %-10s %-15s\n", "Name", "Size", "Status")
-		fmt.Println(strings.Repeat("-", 75))
+	columns := c.Columns
+	if len(columns) == 0 {
+		columns = []string{"score", "key", "content"}
+	}
+	headers := make([]string, len(columns))
+	for i, name := range columns {
+		if field, ok := strings.CutPrefix(name, "metadata."); ok {
+			headers[i] = strings.ToUpper(field)
+			continue
+		}
+		col, ok := queryColumns[name]
+		if !ok {
+			return fmt.Errorf("unknown column: %s", name)
+		}
+		headers[i] = col.header
+	}
+
+	results, err := client.Indexes.QueryWithOptions(c.Name, c.Query, &opperai.QueryOptions{
+		Filters: filters,
+		TopK:    c.TopK,
+	})
+	if err != nil {
+		return err
+	}
+	if c.TopK > 0 && len(results) > c.TopK {
+		results = results[:c.TopK]
+	}
 
-		for _, file := range index.Files {
-			fmt.Printf("%-50s %-10d %-15s\n",
-				truncateString(file.OriginalFilename, 47),
-				file.Size,
-				file.IndexStatus,
-			)
+	switch format {
+	case "json":
+		if results == nil {
+			results = []opperai.RetrievalResponse{}
 		}
-	} else {
-		fmt.Println("\nNo files indexed yet")
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	case "plain":
+		for _, result := range results {
+			fmt.Printf("Score: %f\nKey: %s\nContent: %s\n", result.Score, result.Key, result.Content)
+			if len(result.Metadata) > 0 {
+				fmt.Printf("Metadata: %s\n", formatMetadataValue(result.Metadata))
+			}
+			fmt.Println()
+		}
+		return nil
 	}
 
+	if len(results) == 0 {
+		fmt.Println("No results")
+		return nil
+	}
+
+	rows := make([][]string, len(results))
+	for i, result := range results {
+		rows[i] = make([]string, len(columns))
+		for j, name := range columns {
+			if field, ok := strings.CutPrefix(name, "metadata."); ok {
+				rows[i][j] = formatMetadataValue(result.Metadata[field])
+				continue
+			}
+			rows[i][j] = queryColumns[name].value(result)
+		}
+	}
+	output.Table(headers, rows)
 	return nil
 }
 
-func (c *QueryIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
-	var filter map[string]interface{}
-	if err := json.Unmarshal([]byte(c.Filter), &filter); err != nil {
-		return fmt.Errorf("invalid filter JSON: %v", err)
+// formatMetadataValue prints strings as-is and other values as compact JSON
+func formatMetadataValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return ""
+		}
 	}
 
-	results, err := client.Indexes.Query(c.Name, c.Query, nil) // TODO: Convert filter to []Filter
+	data, err := json.Marshal(v)
 	if err != nil {
-		return err
+		return fmt.Sprint(v)
 	}
+	return string(data)
+}
 
-	// Print results
-	for _, result := range results {
-		fmt.Printf("Score: %f\nContent: %s\n\n", result.Score, result.Content)
-	}
-	return nil
+// singleLine collapses whitespace so multi-line content fits in a table cell
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
 }
 
 func (c *AddToIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	if c.From != "" {
+		return c.addFromFile(client)
+	}
+	if c.Chunk != "" {
+		return c.addChunks(ctx, client)
+	}
+
 	var metadata map[string]interface{}
 	if err := json.Unmarshal([]byte(c.Metadata), &metadata); err != nil {
 		return fmt.Errorf("invalid metadata JSON: %v", err)
@@ -135,19 +214,59 @@ func (c *AddToIndexCommand) Execute(ctx context.Context, client *opperai.Client)
 }
 
 func (c *UploadToIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
-	if _, err := os.Stat(c.FilePath); os.IsNotExist(err) {
+	info, err := os.Stat(c.FilePath)
+	if os.IsNotExist(err) {
 		return fmt.Errorf("file does not exist: %s", c.FilePath)
 	}
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return c.uploadDirectory(ctx, client)
+	}
 
-	err := client.Indexes.UploadFile(c.Name, c.FilePath)
+	file, err := client.Indexes.UploadFileAs(ctx, c.Name, c.FilePath, filepath.Base(c.FilePath))
 	if err != nil {
 		return err
 	}
 
 	fmt.Printf("Uploaded file '%s' to index '%s'\n", c.FilePath, c.Name)
+	if c.Wait {
+		return waitForIndexing(ctx, client, c.Name, []string{file.UUID}, c.Timeout, c.Interval)
+	}
 	return nil
 }
 
+func (c *UploadToIndexCommand) uploadDirectory(ctx context.Context, client *opperai.Client) error {
+	files, err := collectFiles(c.FilePath, c.Recursive, c.Include, c.Exclude)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		if !c.Recursive {
+			return fmt.Errorf("no files to upload in %s (use --recursive to include subdirectories)", c.FilePath)
+		}
+		return fmt.Errorf("no files to upload in %s", c.FilePath)
+	}
+
+	// Fail fast on a missing index instead of once per file
+	if _, err := client.Indexes.Get(ctx, c.Name); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	results := uploadFiles(ctx, client, c.Name, files, c.Concurrency, c.Retries)
+	summaryErr := printUploadSummary(results, time.Since(start))
+
+	if c.Wait {
+		if err := waitForIndexing(ctx, client, c.Name, uploadedFileUUIDs(results), c.Timeout, c.Interval); err != nil {
+			return err
+		}
+	}
+	return summaryErr
+}
+
 // Export the function for testing
 func ExecuteListIndexes(ctx context.Context, client *opperai.Client, w io.Writer, args []string) error {
 	indexes, err := client.Indexes.List("")
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+// addFromFile adds documents from a JSONL file (or stdin for "-") in batches
+func (c *AddToIndexCommand) addFromFile(client *opperai.Client) error {
+	var r io.Reader = os.Stdin
+	if c.From != "-" {
+		f, err := os.Open(c.From)
+		if err != nil {
+			return fmt.Errorf("error opening file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	docs, err := opperai.ReadDocuments(r)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return fmt.Errorf("no documents found in %s", c.From)
+	}
+
+	start := time.Now()
+	added, err := addDocumentBatches(client, c.Name, docs, c.Batch)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Added %d of %d documents to index '%s' in %s\n", added, len(docs), c.Name, time.Since(start).Round(100*time.Millisecond))
+	if failed := len(docs) - added; failed > 0 {
+		return fmt.Errorf("%d documents failed to add", failed)
+	}
+	return nil
+}
+
+// addDocumentBatches adds documents in batches, drawing a progress bar on
+// stderr, and returns the number added. Failed batches are reported and
+// skipped; an error is only returned if the index does not exist.
+func addDocumentBatches(client *opperai.Client, indexName string, docs []opperai.Document, batch int) (int, error) {
+	if batch <= 0 {
+		batch = 100
+	}
+
+	added := 0
+	output.Progress(os.Stderr, 0, len(docs), "")
+	for i := 0; i < len(docs); i += batch {
+		end := min(i+batch, len(docs))
+		if err := client.Indexes.AddBatch(indexName, docs[i:end]); err != nil {
+			if i == 0 && errors.Is(err, opperai.ErrIndexNotFound) {
+				fmt.Fprintln(os.Stderr)
+				return 0, err
+			}
+			fmt.Fprintf(os.Stderr, "\nFAILED documents %d-%d: %v\n", i+1, end, err)
+		} else {
+			added += end - i
+		}
+		output.Progress(os.Stderr, end, len(docs), "")
+	}
+	fmt.Fprintln(os.Stderr)
+
+	return added, nil
+}
+
+func (c *ListIndexDocumentsCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	it := client.Indexes.IterDocuments(c.Name, &opperai.ListOptions{
+		PageSize: c.PageSize,
+		Limit:    c.Limit,
+	})
+
+	switch strings.ToLower(c.Format) {
+	case "jsonl", "json":
+		enc := json.NewEncoder(os.Stdout)
+		for it.Next() {
+			if err := enc.Encode(it.Value()); err != nil {
+				return err
+			}
+		}
+		return it.Err()
+	case "", "table":
+	default:
+		return fmt.Errorf("unknown format: %s (must be table or jsonl)", c.Format)
+	}
+
+	docs, err := it.All()
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		fmt.Println("No documents found")
+		return nil
+	}
+
+	rows := make([][]string, len(docs))
+	for i, doc := range docs {
+		rows[i] = []string{
+			truncateString(doc.Key, 40),
+			truncateString(singleLine(doc.Content), 60),
+			truncateString(formatMetadataValue(doc.Metadata), 50),
+		}
+	}
+	output.Table([]string{"KEY", "CONTENT", "METADATA"}, rows)
+
+	if total := it.Total(); len(docs) < total {
+		fmt.Fprintf(os.Stderr, "\nShowing %d of %d documents, use --all to list everything\n", len(docs), total)
+	}
+	return nil
+}
+
+func (c *GetIndexDocumentCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	doc, err := client.Indexes.GetDocument(c.Name, c.Key)
+	if err != nil {
+		return err
+	}
+
+	if strings.ToLower(c.Format) == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	}
+
+	fmt.Printf("Key: %s\n", doc.Key)
+	if len(doc.Metadata) > 0 {
+		metadata, err := json.MarshalIndent(doc.Metadata, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Metadata: %s\n", metadata)
+	}
+	fmt.Printf("\n%s\n", doc.Content)
+	return nil
+}
+
+func (c *DeleteIndexDocumentsCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	if c.Key != "" {
+		if err := client.Indexes.DeleteDocument(c.Name, c.Key); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted document '%s' from index '%s'\n", c.Key, c.Name)
+		return nil
+	}
+
+	filters, err := opperai.ParseFilters(c.Filters)
+	if err != nil {
+		return err
+	}
+	deleted, err := client.Indexes.DeleteDocuments(c.Name, filters)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Deleted %d documents from index '%s'\n", deleted, c.Name)
+	return nil
+}
@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+func (c *EvalIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	format := strings.ToLower(c.Format)
+	if format != "" && format != "table" && format != "json" {
+		return fmt.Errorf("unknown format: %s (must be table or json)", c.Format)
+	}
+
+	f, err := os.Open(c.Queries)
+	if err != nil {
+		return fmt.Errorf("error opening queries: %w", err)
+	}
+	queries, err := opperai.ReadRetrievalQueries(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("invalid queries file %s: %w", c.Queries, err)
+	}
+	if len(queries) == 0 {
+		return fmt.Errorf("no queries found in %s", c.Queries)
+	}
+
+	var baseline *opperai.RetrievalEvaluation
+	if c.Baseline != "" {
+		data, err := os.ReadFile(c.Baseline)
+		if err != nil {
+			return fmt.Errorf("error reading baseline: %w", err)
+		}
+		if err := json.Unmarshal(data, &baseline); err != nil {
+			return fmt.Errorf("invalid baseline %s: %w", c.Baseline, err)
+		}
+	}
+
+	done := 0
+	output.Progress(os.Stderr, 0, len(queries), "")
+	eval, err := client.Indexes.EvaluateRetrieval(c.Name, queries, opperai.RetrievalEvalOptions{
+		K:           c.K,
+		Concurrency: c.Concurrency,
+		OnResult: func(r opperai.RetrievalQueryResult) {
+			done++
+			output.Progress(os.Stderr, done, len(queries), truncateString(r.ID, 40))
+		},
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(eval); err != nil {
+			return err
+		}
+	} else {
+		printRetrievalEvaluation(eval, baseline)
+	}
+
+	if eval.Metrics.Failed > 0 {
+		return fmt.Errorf("%d of %d queries failed", eval.Metrics.Failed, eval.Metrics.Queries)
+	}
+	return nil
+}
+
+func printRetrievalEvaluation(eval *opperai.RetrievalEvaluation, baseline *opperai.RetrievalEvaluation) {
+	k := eval.Metrics.K
+	rows := make([][]string, 0, len(eval.Queries))
+	for _, q := range eval.Queries {
+		if q.Error != "" {
+			rows = append(rows, []string{q.ID, truncateString(singleLine(q.Query), 40), "-", "-", "-", "-", "error: " + truncateString(q.Error, 40)})
+			continue
+		}
+		rows = append(rows, []string{
+			truncateString(q.ID, 20),
+			truncateString(singleLine(q.Query), 40),
+			fmt.Sprintf("%.2f", q.Recall),
+			fmt.Sprintf("%.2f", q.Precision),
+			fmt.Sprintf("%.2f", q.ReciprocalRank),
+			fmt.Sprintf("%.2f", q.NDCG),
+			truncateString(strings.Join(q.Misses, ", "), 50),
+		})
+	}
+	output.Table([]string{"ID", "QUERY", fmt.Sprintf("RECALL@%d", k), fmt.Sprintf("P@%d", k), "RR", fmt.Sprintf("NDCG@%d", k), "MISSES"}, rows)
+
+	m := eval.Metrics
+	fmt.Printf("\nIndex '%s', %d queries", eval.Index, m.Queries)
+	if m.Failed > 0 {
+		fmt.Printf(" (%d failed)", m.Failed)
+	}
+	fmt.Println()
+
+	metrics := []struct {
+		name  string
+		value float64
+		base  func(opperai.RetrievalMetrics) float64
+	}{
+		{fmt.Sprintf("Recall@%d", k), m.Recall, func(b opperai.RetrievalMetrics) float64 { return b.Recall }},
+		{fmt.Sprintf("Precision@%d", k), m.Precision, func(b opperai.RetrievalMetrics) float64 { return b.Precision }},
+		{"MRR", m.MRR, func(b opperai.RetrievalMetrics) float64 { return b.MRR }},
+		{fmt.Sprintf("nDCG@%d", k), m.NDCG, func(b opperai.RetrievalMetrics) float64 { return b.NDCG }},
+	}
+	for _, metric := range metrics {
+		fmt.Printf("  %-14s %.3f", metric.name, metric.value)
+		if baseline != nil {
+			fmt.Printf("  (%+.3f vs baseline)", metric.value-metric.base(baseline.Metrics))
+		}
+		fmt.Println()
+	}
+	if baseline != nil && baseline.Metrics.K != k {
+		fmt.Fprintf(os.Stderr, "\nNote: baseline was run with k=%d, this run with k=%d\n", baseline.Metrics.K, k)
+	}
+}
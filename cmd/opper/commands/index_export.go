@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/cmd/opper/config"
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+func (c *ExportIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	export, err := exportIndex(client, c.Name)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if c.Output != "" && c.Output != "-" {
+		f, err := os.Create(c.Output)
+		if err != nil {
+			return fmt.Errorf("error creating file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := opperai.WriteExport(w, export); err != nil {
+		return fmt.Errorf("error writing export: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d documents and %d file entries from index '%s'\n", len(export.Documents), len(export.Files), c.Name)
+	return nil
+}
+
+func (c *ImportIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	var r io.Reader = os.Stdin
+	if c.File != "-" {
+		f, err := os.Open(c.File)
+		if err != nil {
+			return fmt.Errorf("error opening file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	export, err := opperai.ReadExport(r)
+	if err != nil {
+		return err
+	}
+
+	return importDocuments(ctx, client, c.Name, export, c.Batch, "Imported")
+}
+
+func (c *CopyIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	dest := client
+	if c.DestKey != "" {
+		apiKey, baseUrl, err := config.GetAPIKeyAndBaseUrl(c.DestKey)
+		if err != nil {
+			return fmt.Errorf("error loading key %q: %w", c.DestKey, err)
+		}
+		dest = opperai.NewClient(apiKey, baseUrl)
+	} else if c.Source == c.Dest {
+		return fmt.Errorf("source and destination are the same index")
+	}
+
+	export, err := exportIndex(client, c.Source)
+	if err != nil {
+		return err
+	}
+
+	return importDocuments(ctx, dest, c.Dest, export, c.Batch, "Copied")
+}
+
+// exportIndex reads an index with a progress bar on stderr
+func exportIndex(client *opperai.Client, name string) (*opperai.IndexExport, error) {
+	export, err := client.Indexes.Export(name, func(done, total int) {
+		if done%100 == 0 || done == total {
+			output.Progress(os.Stderr, done, total, "exporting")
+		}
+	})
+	if export != nil && len(export.Documents) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	return export, err
+}
+
+// importDocuments adds the documents of an export to an index, creating the
+// index if it does not exist. Files cannot be copied, so they are listed for
+// the user to upload again.
+func importDocuments(ctx context.Context, client *opperai.Client, name string, export *opperai.IndexExport, batch int, verb string) error {
+	if _, err := client.Indexes.Get(ctx, name); err != nil {
+		if !errors.Is(err, opperai.ErrIndexNotFound) {
+			return err
+		}
+		if _, err := client.Indexes.Create(name); err != nil {
+			return err
+		}
+		fmt.Printf("Created index '%s'\n", name)
+	}
+
+	start := time.Now()
+	added := 0
+	if len(export.Documents) > 0 {
+		var err error
+		if added, err = addDocumentBatches(client, name, export.Documents, batch); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%s %d of %d documents into index '%s' in %s\n", verb, added, len(export.Documents), name, time.Since(start).Round(100*time.Millisecond))
+
+	if len(export.Files) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d uploaded files were not copied, upload them again with 'opper indexes upload':\n", len(export.Files))
+		for _, f := range export.Files {
+			fmt.Fprintf(os.Stderr, "  %s\n", f.OriginalFilename)
+		}
+	}
+
+	if failed := len(export.Documents) - added; failed > 0 {
+		return fmt.Errorf("%d of %d documents failed", failed, len(export.Documents))
+	}
+	return nil
+}
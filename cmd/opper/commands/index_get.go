@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+// fileStatusOrder is the order statuses are summarized in
+var fileStatusOrder = []string{
+	opperai.FileStatusCompleted,
+	opperai.FileStatusProcessing,
+	opperai.FileStatusPending,
+	opperai.FileStatusFailed,
+}
+
+func (c *GetIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	switch c.Sort {
+	case "", "name", "size", "date", "status":
+	default:
+		return fmt.Errorf("unknown sort: %s (must be name, size, date or status)", c.Sort)
+	}
+
+	if !c.Watch {
+		index, err := client.Indexes.Get(ctx, c.Name)
+		if err != nil {
+			return err
+		}
+		c.print(index)
+		return nil
+	}
+
+	interval := c.Interval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	for {
+		index, err := client.Indexes.Get(ctx, c.Name)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		// Clear the screen and redraw from the top
+		fmt.Print("\033[H\033[2J")
+		c.print(index)
+
+		if indexingDone(index.Files) {
+			fmt.Println("\nIndexing complete")
+			return nil
+		}
+		fmt.Printf("\nRefreshing every %s, press Ctrl+C to stop\n", interval)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *GetIndexCommand) print(index *opperai.Index) {
+	fmt.Printf("Index: %s\n", index.Name)
+	fmt.Printf("Created: %s\n", index.CreatedAt.Format(time.RFC3339))
+
+	if len(index.Files) == 0 {
+		fmt.Println("\nNo files indexed yet")
+		return
+	}
+
+	var total int64
+	counts := map[string]int{}
+	for _, f := range index.Files {
+		total += f.Size
+		counts[f.IndexStatus]++
+	}
+	fmt.Printf("Files: %d (%s)\n", len(index.Files), formatBytes(total))
+	fmt.Printf("Status: %s\n", formatStatusCounts(counts))
+
+	files := c.filterFiles(index.Files)
+	if len(files) == 0 {
+		fmt.Println("\nNo files match the filters")
+		return
+	}
+	sortFiles(files, c.Sort, c.Reverse)
+
+	shown := files
+	if c.Limit > 0 && len(shown) > c.Limit {
+		shown = shown[:c.Limit]
+	}
+
+	rows := make([][]string, len(shown))
+	for i, f := range shown {
+		rows[i] = []string{
+			truncateString(f.OriginalFilename, 60),
+			formatBytes(f.Size),
+			f.IndexStatus,
+			formatFileDate(f.CreatedAt),
+		}
+	}
+	fmt.Println()
+	output.Table([]string{"FILE", "SIZE", "STATUS", "UPLOADED"}, rows)
+	if len(shown) < len(files) {
+		fmt.Printf("\nShowing %d of %d files, use --limit 0 to list everything\n", len(shown), len(files))
+	}
+
+	var failed []opperai.File
+	for _, f := range files {
+		if f.Failed() {
+			failed = append(failed, f)
+		}
+	}
+	if len(failed) > 0 {
+		fmt.Println("\nFailed files:")
+		for _, f := range failed {
+			reason := f.IndexStatusMessage
+			if reason == "" {
+				reason = "no reason given"
+			}
+			fmt.Printf("  %s: %s\n", f.OriginalFilename, singleLine(reason))
+		}
+	}
+}
+
+// filterFiles returns the files matching the status and name filters
+func (c *GetIndexCommand) filterFiles(files []opperai.File) []opperai.File {
+	statuses := map[string]bool{}
+	for _, s := range c.Status {
+		for _, part := range strings.Split(s, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				statuses[strings.ToLower(part)] = true
+			}
+		}
+	}
+
+	var out []opperai.File
+	for _, f := range files {
+		if len(statuses) > 0 && !statuses[strings.ToLower(f.IndexStatus)] {
+			continue
+		}
+		if c.Match != "" && !matchGlob(c.Match, f.OriginalFilename) {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func sortFiles(files []opperai.File, by string, reverse bool) {
+	less := func(a, b opperai.File) bool { return a.OriginalFilename < b.OriginalFilename }
+	switch by {
+	case "size":
+		less = func(a, b opperai.File) bool { return a.Size > b.Size }
+	case "date":
+		less = func(a, b opperai.File) bool { return a.CreatedAt.After(b.CreatedAt) }
+	case "status":
+		less = func(a, b opperai.File) bool { return statusRank(a) < statusRank(b) }
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if reverse {
+			return less(files[j], files[i])
+		}
+		return less(files[i], files[j])
+	})
+}
+
+// statusRank orders failed files first, then in-progress ones
+func statusRank(f opperai.File) int {
+	switch {
+	case f.Failed():
+		return 0
+	case !f.Done():
+		return 1
+	default:
+		return 2
+	}
+}
+
+// formatStatusCounts returns counts such as "120 completed, 3 failed", with
+// unknown statuses after the known ones
+func formatStatusCounts(counts map[string]int) string {
+	var parts []string
+	seen := map[string]bool{}
+	for _, status := range fileStatusOrder {
+		seen[status] = true
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+
+	var other []string
+	for status := range counts {
+		if !seen[status] {
+			other = append(other, status)
+		}
+	}
+	sort.Strings(other)
+	for _, status := range other {
+		name := status
+		if name == "" {
+			name = "unknown"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", counts[status], name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// indexingDone reports whether no file is waiting to be indexed
+func indexingDone(files []opperai.File) bool {
+	for _, f := range files {
+		if !f.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+func formatFileDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
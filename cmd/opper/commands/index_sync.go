@@ -0,0 +1,308 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+// syncManifest records what was last uploaded from a directory so later syncs
+// only upload files whose content changed
+type syncManifest struct {
+	Index string                       `json:"index"`
+	Files map[string]syncManifestEntry `json:"files"`
+}
+
+type syncManifestEntry struct {
+	SHA256     string    `json:"sha256"`
+	Size       int64     `json:"size"`
+	ModTime    time.Time `json:"mod_time"`
+	UUID       string    `json:"uuid"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// syncAction is a planned change to the index
+type syncAction struct {
+	Kind   string // "new", "changed" or "delete"
+	File   localFile
+	Hash   string
+	Remote []opperai.File // existing remote copies, replaced or deleted
+}
+
+func defaultManifestPath(dir, indexName string) string {
+	return filepath.Join(dir, fmt.Sprintf(".opper-sync-%s.json", indexName))
+}
+
+func (c *SyncIndexCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	info, err := os.Stat(c.Dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", c.Dir)
+	}
+
+	manifestPath := c.Manifest
+	if manifestPath == "" {
+		manifestPath = defaultManifestPath(c.Dir, c.Name)
+	}
+	manifest, err := loadSyncManifest(manifestPath, c.Name)
+	if err != nil {
+		return err
+	}
+
+	index, err := client.Indexes.Get(ctx, c.Name)
+	if err != nil {
+		return err
+	}
+
+	files, err := collectFiles(c.Dir, true, c.Include, c.Exclude)
+	if err != nil {
+		return err
+	}
+
+	actions, unchanged, err := planSync(files, index.Files, manifest, c.Include, c.Exclude)
+	if err != nil {
+		return err
+	}
+
+	var uploads []localFile
+	var deletes []syncAction
+	counts := map[string]int{}
+	for _, action := range actions {
+		counts[action.Kind]++
+		if action.Kind == "delete" {
+			if c.Delete {
+				deletes = append(deletes, action)
+			}
+			continue
+		}
+		uploads = append(uploads, action.File)
+	}
+
+	fmt.Printf("%d new, %d changed, %d unchanged", counts["new"], counts["changed"], unchanged)
+	if c.Delete {
+		fmt.Printf(", %d to delete\n", counts["delete"])
+	} else {
+		fmt.Printf(", %d only in index (use --delete to remove)\n", counts["delete"])
+	}
+
+	if c.DryRun {
+		if len(actions) > 0 {
+			rows := make([][]string, 0, len(actions))
+			for _, action := range actions {
+				if action.Kind == "delete" && !c.Delete {
+					continue
+				}
+				size := formatBytes(action.File.Size)
+				if action.Kind == "delete" {
+					size = formatBytes(action.Remote[0].Size)
+				}
+				rows = append(rows, []string{action.Kind, action.File.RelPath, size})
+			}
+			fmt.Println()
+			output.Table([]string{"ACTION", "FILE", "SIZE"}, rows)
+		}
+		fmt.Println("\nDry run, no changes made")
+		return nil
+	}
+
+	start := time.Now()
+	var uploaded, failed int
+	var uploadedUUIDs []string
+
+	if len(uploads) > 0 {
+		results := uploadFiles(ctx, client, c.Name, uploads, c.Concurrency, c.Retries)
+		uploadedUUIDs = uploadedFileUUIDs(results)
+		byPath := make(map[string]syncAction, len(actions))
+		for _, action := range actions {
+			byPath[action.File.RelPath] = action
+		}
+
+		for _, r := range results {
+			if r.Err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "FAILED %s: %v\n", r.File.RelPath, r.Err)
+				continue
+			}
+
+			uploaded++
+			action := byPath[r.File.RelPath]
+			manifest.Files[r.File.RelPath] = syncManifestEntry{
+				SHA256:     action.Hash,
+				Size:       r.File.Size,
+				ModTime:    fileModTime(r.File.Path),
+				UUID:       r.Remote.UUID,
+				UploadedAt: time.Now().UTC(),
+			}
+
+			// Remove the previous copies only once the new one is in place
+			for _, old := range action.Remote {
+				if err := client.Indexes.DeleteFile(c.Name, old.UUID); err != nil {
+					failed++
+					fmt.Fprintf(os.Stderr, "FAILED removing old copy of %s: %v\n", r.File.RelPath, err)
+				}
+			}
+		}
+	}
+
+	deleted := 0
+	for _, action := range deletes {
+		ok := true
+		for _, remote := range action.Remote {
+			if err := client.Indexes.DeleteFile(c.Name, remote.UUID); err != nil {
+				ok = false
+				failed++
+				fmt.Fprintf(os.Stderr, "FAILED deleting %s: %v\n", action.File.RelPath, err)
+			}
+		}
+		if ok {
+			deleted++
+			delete(manifest.Files, action.File.RelPath)
+		}
+	}
+
+	if err := saveSyncManifest(manifestPath, manifest); err != nil {
+		return err
+	}
+
+	fmt.Printf("Synced index '%s' in %s: uploaded %d, deleted %d", c.Name, time.Since(start).Round(100*time.Millisecond), uploaded, deleted)
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+
+	if c.Wait {
+		if err := waitForIndexing(ctx, client, c.Name, uploadedUUIDs, c.Timeout, c.Interval); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d sync operations failed", failed)
+	}
+	return nil
+}
+
+// planSync compares local files with the index. A file is unchanged when the
+// manifest has the same content hash and the uploaded copy is still in the
+// index; files the manifest does not know are uploaded again because the
+// index does not expose content hashes. Remote files matching the
+// include/exclude filters but missing locally are planned for deletion.
+func planSync(files []localFile, remoteFiles []opperai.File, manifest *syncManifest, include, exclude []string) ([]syncAction, int, error) {
+	remote := map[string][]opperai.File{}
+	for _, f := range remoteFiles {
+		remote[f.OriginalFilename] = append(remote[f.OriginalFilename], f)
+	}
+
+	var actions []syncAction
+	unchanged := 0
+	local := make(map[string]bool, len(files))
+
+	for _, file := range files {
+		local[file.RelPath] = true
+		entry, known := manifest.Files[file.RelPath]
+
+		hash := entry.SHA256
+		if !known || entry.Size != file.Size || !entry.ModTime.Equal(fileModTime(file.Path)) {
+			var err error
+			if hash, err = hashFile(file.Path); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		copies := remote[file.RelPath]
+		switch {
+		case len(copies) == 0:
+			actions = append(actions, syncAction{Kind: "new", File: file, Hash: hash})
+		case known && entry.SHA256 == hash && len(copies) == 1 && copies[0].UUID == entry.UUID:
+			unchanged++
+		default:
+			actions = append(actions, syncAction{Kind: "changed", File: file, Hash: hash, Remote: copies})
+		}
+	}
+
+	names := make([]string, 0, len(remote))
+	for name := range remote {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if local[name] || (len(include) > 0 && !matchAny(include, name)) || matchAny(exclude, name) {
+			continue
+		}
+		actions = append(actions, syncAction{
+			Kind:   "delete",
+			File:   localFile{RelPath: name},
+			Remote: remote[name],
+		})
+	}
+
+	return actions, unchanged, nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("error hashing %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime().UTC()
+}
+
+func loadSyncManifest(path, indexName string) (*syncManifest, error) {
+	manifest := &syncManifest{Index: indexName, Files: map[string]syncManifestEntry{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	if manifest.Index != indexName {
+		return nil, fmt.Errorf("manifest %s belongs to index %q, not %q", path, manifest.Index, indexName)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]syncManifestEntry{}
+	}
+	return manifest, nil
+}
+
+func saveSyncManifest(path string, manifest *syncManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+// localFile is a file found below an upload root
+type localFile struct {
+	Path    string // path on disk
+	RelPath string // slash-separated path relative to the root, used as the remote filename
+	Size    int64
+}
+
+// uploadResult is the outcome of uploading one file
+type uploadResult struct {
+	File     localFile
+	Remote   *opperai.File
+	Attempts int
+	Err      error
+}
+
+// collectFiles returns the regular files below root, sorted by relative path.
+// Without recursive only the files directly in root are returned. Hidden
+// files and directories are skipped. Include patterns select files and
+// exclude patterns remove files or whole directories; see matchGlob.
+func collectFiles(root string, recursive bool, include, exclude []string) ([]localFile, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	var files []localFile
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if !recursive || strings.HasPrefix(d.Name(), ".") || matchAny(exclude, rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		if len(include) > 0 && !matchAny(include, rel) {
+			return nil
+		}
+		if matchAny(exclude, rel) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, localFile{Path: p, RelPath: rel, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].RelPath < files[j].RelPath })
+	return files, nil
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether a slash-separated relative path matches a glob
+// pattern. A pattern without a slash, such as "*.md" or "vendor", matches
+// the name of the file or of any directory it is in. Other patterns match
+// the whole path, with "**" matching any number of directories, so
+// "vendor/**" matches everything below vendor.
+func matchGlob(pattern, rel string) bool {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	parts := strings.Split(rel, "/")
+
+	if !strings.Contains(pattern, "/") {
+		for _, part := range parts {
+			if ok, _ := path.Match(pattern, part); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	return matchSegments(strings.Split(strings.TrimSuffix(pattern, "/"), "/"), parts)
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Try every possible number of directories for **
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// uploadFiles uploads files with a pool of workers, retrying failed uploads
+// with exponential backoff and drawing a progress bar on stderr. Results are
+// returned in the order of files.
+func uploadFiles(ctx context.Context, client *opperai.Client, indexName string, files []localFile, concurrency, retries int) []uploadResult {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]uploadResult, len(files))
+	jobs := make(chan int)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	done, failed := 0, 0
+	output.Progress(os.Stderr, 0, len(files), "")
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := uploadWithRetry(ctx, client, indexName, files[i], retries)
+
+				mu.Lock()
+				results[i] = result
+				done++
+				label := files[i].RelPath
+				if result.Err != nil {
+					failed++
+				}
+				if failed > 0 {
+					label = fmt.Sprintf("(%d failed) %s", failed, label)
+				}
+				output.Progress(os.Stderr, done, len(files), truncateString(label, 60))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range files {
+		if ctx.Err() != nil {
+			results[i] = uploadResult{File: files[i], Err: ctx.Err()}
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	fmt.Fprintln(os.Stderr)
+
+	return results
+}
+
+func uploadWithRetry(ctx context.Context, client *opperai.Client, indexName string, file localFile, retries int) uploadResult {
+	result := uploadResult{File: file}
+	backoff := time.Second
+
+	for {
+		result.Attempts++
+		result.Remote, result.Err = client.Indexes.UploadFileAs(ctx, indexName, file.Path, file.RelPath)
+		if result.Err == nil || result.Attempts > retries || !retryableUploadError(result.Err) {
+			return result
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Err = ctx.Err()
+			return result
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// retryableUploadError reports whether an upload might succeed if tried
+// again: network errors, rate limits and server errors
+func retryableUploadError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *opperai.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// printUploadSummary reports failed files and totals, and returns an error
+// if any upload failed
+func printUploadSummary(results []uploadResult, elapsed time.Duration) error {
+	var uploaded, failed int
+	var bytes int64
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "FAILED %s: %v\n", r.File.RelPath, r.Err)
+			continue
+		}
+		uploaded++
+		bytes += r.File.Size
+		if r.Attempts > 1 {
+			fmt.Fprintf(os.Stderr, "retried %s: succeeded after %d attempts\n", r.File.RelPath, r.Attempts)
+		}
+	}
+
+	fmt.Printf("Uploaded %d of %d files (%s) in %s", uploaded, len(results), formatBytes(bytes), elapsed.Round(100*time.Millisecond))
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed to upload", failed, len(results))
+	}
+	return nil
+}
+
+// formatBytes returns a human readable size such as "1.2 MB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// waitForIndexing polls until the uploaded files are indexed, showing
+// progress on stderr, and reports files that failed to index
+func waitForIndexing(ctx context.Context, client *opperai.Client, indexName string, fileUUIDs []string, timeout, interval time.Duration) error {
+	if len(fileUUIDs) == 0 {
+		return nil
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	start := time.Now()
+	files, err := client.Indexes.WaitForIndexed(ctx, indexName, fileUUIDs, interval, func(files []opperai.File) {
+		indexed, failed := 0, 0
+		for _, f := range files {
+			switch {
+			case f.Failed():
+				failed++
+			case f.Done():
+				indexed++
+			}
+		}
+		label := "indexing"
+		if failed > 0 {
+			label = fmt.Sprintf("indexing (%d failed)", failed)
+		}
+		output.Progress(os.Stderr, indexed, len(files), label)
+	})
+	fmt.Fprintln(os.Stderr)
+
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("error waiting for indexing: %w", err)
+	}
+
+	var pending, failed int
+	for _, f := range files {
+		switch {
+		case f.Failed():
+			failed++
+			reason := f.IndexStatusMessage
+			if reason == "" {
+				reason = f.IndexStatus
+			}
+			fmt.Fprintf(os.Stderr, "FAILED indexing %s: %s\n", f.OriginalFilename, reason)
+		case !f.Done():
+			pending++
+		}
+	}
+
+	switch {
+	case pending > 0 && timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("timed out after %s waiting for %d of %d files to be indexed", timeout, pending, len(files))
+	case pending > 0:
+		return fmt.Errorf("stopped waiting for %d of %d files to be indexed: %w", pending, len(files), ctx.Err())
+	case failed > 0:
+		return fmt.Errorf("%d of %d files failed to index", failed, len(files))
+	}
+	fmt.Printf("Indexing complete (%d files, %s)\n", len(files), time.Since(start).Round(100*time.Millisecond))
+	return nil
+}
+
+// uploadedFileUUIDs returns the remote UUIDs of successful uploads
+func uploadedFileUUIDs(results []uploadResult) []string {
+	var uuids []string
+	for _, r := range results {
+		if r.Err == nil && r.Remote != nil {
+			uuids = append(uuids, r.Remote.UUID)
+		}
+	}
+	return uuids
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+func TestRetryableUploadError(t *testing.T) {
+	status := func(code int) error {
+		return &opperai.StatusError{Op: "register file", StatusCode: code, Status: fmt.Sprint(code)}
+	}
+	netErr := &url.Error{Op: "Post", URL: "https://api.opper.ai", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "rate limited", err: status(429), want: true},
+		{name: "server error", err: status(500), want: true},
+		{name: "bad gateway", err: status(502), want: true},
+		{name: "bad request", err: status(400)},
+		{name: "unauthorized", err: status(401)},
+		{name: "forbidden", err: status(403)},
+		{name: "too large", err: status(413)},
+		{name: "wrapped status", err: fmt.Errorf("upload: %w", status(503)), want: true},
+		{name: "network error", err: netErr, want: true},
+		{name: "cancelled", err: &url.Error{Op: "Post", URL: "https://api.opper.ai", Err: context.Canceled}},
+		{name: "index not found", err: fmt.Errorf("%w: docs", opperai.ErrIndexNotFound)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryableUploadError(tt.err); got != tt.want {
+				t.Errorf("retryableUploadError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// validateJSONSchema checks value against the commonly used subset of JSON
+// Schema: type, enum, const, properties, required, additionalProperties,
+// items, min/max items, min/max length, pattern and minimum/maximum. It
+// returns one message per violation.
+func validateJSONSchema(schema map[string]interface{}, value interface{}, path string) []string {
+	var errs []string
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	if t, ok := schema["type"]; ok {
+		var types []string
+		switch t := t.(type) {
+		case string:
+			types = []string{t}
+		case []interface{}:
+			for _, v := range t {
+				if s, ok := v.(string); ok {
+					types = append(types, s)
+				}
+			}
+		}
+		matched := false
+		for _, typ := range types {
+			if jsonTypeMatches(typ, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("expected %v, got %s", t, jsonTypeName(value))
+			return errs
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, v := range enum {
+			if reflect.DeepEqual(v, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value %s is not one of %s", compactJSON(value), compactJSON(enum))
+		}
+	}
+
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		fail("value %s does not equal %s", compactJSON(value), compactJSON(c))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if s, ok := name.(string); ok {
+					if _, present := v[s]; !present {
+						fail("missing required property %q", s)
+					}
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if sub, ok := properties[key].(map[string]interface{}); ok {
+				errs = append(errs, validateJSONSchema(sub, v[key], path+"."+key)...)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					fail("unexpected property %q", key)
+				}
+			case map[string]interface{}:
+				errs = append(errs, validateJSONSchema(additional, v[key], path+"."+key)...)
+			}
+		}
+
+	case []interface{}:
+		if n, ok := schemaNumber(schema, "minItems"); ok && float64(len(v)) < n {
+			fail("expected at least %v items, got %d", n, len(v))
+		}
+		if n, ok := schemaNumber(schema, "maxItems"); ok && float64(len(v)) > n {
+			fail("expected at most %v items, got %d", n, len(v))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				errs = append(errs, validateJSONSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if n, ok := schemaNumber(schema, "minLength"); ok && length < n {
+			fail("expected at least %v characters, got %v", n, length)
+		}
+		if n, ok := schemaNumber(schema, "maxLength"); ok && length > n {
+			fail("expected at most %v characters, got %v", n, length)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fail("invalid pattern %q: %v", pattern, err)
+			} else if !re.MatchString(v) {
+				fail("%q does not match pattern %q", v, pattern)
+			}
+		}
+
+	case float64:
+		if n, ok := schemaNumber(schema, "minimum"); ok && v < n {
+			fail("%v is less than minimum %v", v, n)
+		}
+		if n, ok := schemaNumber(schema, "maximum"); ok && v > n {
+			fail("%v is greater than maximum %v", v, n)
+		}
+	}
+
+	return errs
+}
+
+func jsonTypeMatches(typ string, value interface{}) bool {
+	switch typ {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonTypeName(value) == typ
+	}
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	return n, ok
+}
+
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestValidateJSONSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		value  string
+		want   []string
+	}{
+		{
+			name:   "matching object",
+			schema: `{"type":"object","required":["name"],"properties":{"name":{"type":"string"},"age":{"type":"integer","minimum":0}}}`,
+			value:  `{"name":"Ada","age":36}`,
+		},
+		{
+			name:   "wrong type stops validation",
+			schema: `{"type":"object","required":["name"]}`,
+			value:  `[1]`,
+			want:   []string{"$: expected object, got array"},
+		},
+		{
+			name:   "type list",
+			schema: `{"type":["string","null"]}`,
+			value:  `null`,
+		},
+		{
+			name:   "integer rejects fractions",
+			schema: `{"type":"integer"}`,
+			value:  `1.5`,
+			want:   []string{"$: expected integer, got number"},
+		},
+		{
+			name:   "missing required and nested violation",
+			schema: `{"type":"object","required":["name"],"properties":{"age":{"type":"number","minimum":0}}}`,
+			value:  `{"age":-1}`,
+			want: []string{
+				`$: missing required property "name"`,
+				"$.age: -1 is less than minimum 0",
+			},
+		},
+		{
+			name:   "additional properties false",
+			schema: `{"properties":{"a":{}},"additionalProperties":false}`,
+			value:  `{"a":1,"c":2,"b":3}`,
+			want: []string{
+				`$: unexpected property "b"`,
+				`$: unexpected property "c"`,
+			},
+		},
+		{
+			name:   "additional properties schema",
+			schema: `{"additionalProperties":{"type":"string"}}`,
+			value:  `{"a":1}`,
+			want:   []string{"$.a: expected string, got number"},
+		},
+		{
+			name:   "enum and const",
+			schema: `{"enum":["red","green"],"const":"red"}`,
+			value:  `"blue"`,
+			want: []string{
+				`$: value "blue" is not one of ["red","green"]`,
+				`$: value "blue" does not equal "red"`,
+			},
+		},
+		{
+			name:   "array items and bounds",
+			schema: `{"type":"array","minItems":3,"items":{"type":"string"}}`,
+			value:  `["a",2]`,
+			want: []string{
+				"$: expected at least 3 items, got 2",
+				"$[1]: expected string, got number",
+			},
+		},
+		{
+			name:   "string length counts runes",
+			schema: `{"type":"string","maxLength":3}`,
+			value:  `"ééé"`,
+		},
+		{
+			name:   "string pattern",
+			schema: `{"type":"string","pattern":"^[a-z]+$","minLength":2}`,
+			value:  `"A"`,
+			want: []string{
+				"$: expected at least 2 characters, got 1",
+				`$: "A" does not match pattern "^[a-z]+$"`,
+			},
+		},
+		{
+			name:   "invalid pattern",
+			schema: `{"pattern":"("}`,
+			value:  `"x"`,
+			want:   []string{"$: invalid pattern \"(\": error parsing regexp: missing closing ): `(`"},
+		},
+		{
+			name:   "maximum",
+			schema: `{"maximum":10}`,
+			value:  `11`,
+			want:   []string{"$: 11 is greater than maximum 10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatal(err)
+			}
+			got := validateJSONSchema(schema, value, "$")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateJSONSchema() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
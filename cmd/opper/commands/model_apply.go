@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/opperai"
+	"gopkg.in/yaml.v3"
+)
+
+// modelsFile is the format read by models apply
+type modelsFile struct {
+	Models []modelSpec `yaml:"models"`
+}
+
+type modelSpec struct {
+	Name       string                 `yaml:"name"`
+	Identifier string                 `yaml:"identifier"`
+	APIKeyEnv  string                 `yaml:"api_key_env"`
+	APIKeyFile string                 `yaml:"api_key_file"`
+	Extra      map[string]interface{} `yaml:"extra"`
+	// APIKey is only decoded to reject keys stored in the file
+	APIKey string `yaml:"api_key"`
+}
+
+var envRefRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// loadModelsFile reads model definitions, expanding ${VAR} references in
+// identifiers and extra values so one file can serve several environments.
+// Relative api_key_file paths are resolved against the file's directory.
+func loadModelsFile(path string) ([]modelSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading models file: %w", err)
+	}
+
+	var file modelsFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid models file %s: %w", path, err)
+	}
+
+	seen := map[string]bool{}
+	for i := range file.Models {
+		m := &file.Models[i]
+		switch {
+		case m.Name == "":
+			return nil, fmt.Errorf("model %d: name is required", i+1)
+		case m.Identifier == "":
+			return nil, fmt.Errorf("model %s: identifier is required", m.Name)
+		case m.APIKey != "":
+			return nil, fmt.Errorf("model %s: api_key must not be stored in the file, use api_key_env or api_key_file", m.Name)
+		case m.APIKeyEnv != "" && m.APIKeyFile != "":
+			return nil, fmt.Errorf("model %s: use only one of api_key_env and api_key_file", m.Name)
+		case seen[m.Name]:
+			return nil, fmt.Errorf("model %s is defined more than once", m.Name)
+		}
+		seen[m.Name] = true
+
+		if m.Identifier, err = expandEnvRefs(m.Identifier); err != nil {
+			return nil, fmt.Errorf("model %s: %w", m.Name, err)
+		}
+		for k, v := range m.Extra {
+			if m.Extra[k], err = expandEnvValue(v); err != nil {
+				return nil, fmt.Errorf("model %s: extra.%s: %w", m.Name, k, err)
+			}
+		}
+		if m.APIKeyFile != "" && !filepath.IsAbs(m.APIKeyFile) {
+			m.APIKeyFile = filepath.Join(filepath.Dir(path), m.APIKeyFile)
+		}
+	}
+
+	return file.Models, nil
+}
+
+func expandEnvRefs(s string) (string, error) {
+	var missing []string
+	out := envRefRe.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envRefRe.FindStringSubmatch(ref)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+func expandEnvValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return expandEnvRefs(v)
+	case map[string]interface{}:
+		for k, item := range v {
+			expanded, err := expandEnvValue(item)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = expanded
+		}
+	case []interface{}:
+		for i, item := range v {
+			expanded, err := expandEnvValue(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+	}
+	return v, nil
+}
+
+func (c *ApplyModelsCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	specs, err := loadModelsFile(c.File)
+	if err != nil {
+		return err
+	}
+
+	current, err := client.Models.List(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing models: %w", err)
+	}
+
+	desired := make([]opperai.CustomLanguageModel, len(specs))
+	bySpec := make(map[string]modelSpec, len(specs))
+	for i, spec := range specs {
+		if spec.Extra == nil {
+			spec.Extra = map[string]interface{}{}
+		}
+		desired[i] = opperai.CustomLanguageModel{Name: spec.Name, Identifier: spec.Identifier, Extra: spec.Extra}
+		bySpec[spec.Name] = spec
+	}
+
+	changes := opperai.PlanModelChanges(current, desired, c.Prune)
+	if c.UpdateKeys {
+		for i := range changes {
+			spec, ok := bySpec[changes[i].Model.Name]
+			if ok && changes[i].Action != opperai.ModelActionCreate && (spec.APIKeyEnv != "" || spec.APIKeyFile != "") {
+				changes[i].Action = opperai.ModelActionUpdate
+				changes[i].Fields = append(changes[i].Fields, "api_key")
+			}
+		}
+	}
+
+	counts := map[string]int{}
+	rows := make([][]string, 0, len(changes))
+	for _, change := range changes {
+		counts[change.Action]++
+		rows = append(rows, []string{change.Action, change.Model.Name, change.Model.Identifier, strings.Join(change.Fields, ", ")})
+	}
+	if len(rows) > 0 {
+		output.Table([]string{"ACTION", "MODEL", "IDENTIFIER", "CHANGES"}, rows)
+		fmt.Println()
+	}
+	fmt.Printf("%d to create, %d to update, %d to delete, %d unchanged\n",
+		counts[opperai.ModelActionCreate], counts[opperai.ModelActionUpdate], counts[opperai.ModelActionDelete], counts[opperai.ModelActionUnchanged])
+
+	if c.DryRun {
+		fmt.Println("Dry run, no changes made")
+		return nil
+	}
+
+	// Read every key before changing anything, so a missing secret does not
+	// leave the models half applied
+	keys := map[string]string{}
+	for _, change := range changes {
+		spec := bySpec[change.Model.Name]
+		needsKey := change.Action == opperai.ModelActionCreate ||
+			(change.Action == opperai.ModelActionUpdate && c.UpdateKeys)
+		if !needsKey || (spec.APIKeyEnv == "" && spec.APIKeyFile == "") {
+			if change.Action == opperai.ModelActionCreate {
+				return fmt.Errorf("model %s: api_key_env or api_key_file is required to create it", spec.Name)
+			}
+			continue
+		}
+		key, err := resolveAPIKey("", spec.APIKeyFile, spec.APIKeyEnv, "")
+		if err != nil {
+			return fmt.Errorf("model %s: %w", spec.Name, err)
+		}
+		keys[spec.Name] = key
+	}
+
+	failed := 0
+	for _, change := range changes {
+		name := change.Model.Name
+		var err error
+		switch change.Action {
+		case opperai.ModelActionCreate:
+			model := change.Model
+			model.APIKey = keys[name]
+			err = client.Models.Create(ctx, model)
+		case opperai.ModelActionUpdate:
+			// Always send extra so keys removed from the file are cleared
+			extra := change.Model.Extra
+			if extra == nil {
+				extra = map[string]interface{}{}
+			}
+			err = client.Models.Update(ctx, name, opperai.CustomLanguageModel{
+				Identifier: change.Model.Identifier,
+				APIKey:     keys[name],
+				Extra:      extra,
+			})
+		case opperai.ModelActionDelete:
+			var ok bool
+			if ok, err = ConfirmDeletion("model", name, c.Yes); err == nil && !ok {
+				fmt.Printf("Skipped deleting %s\n", name)
+				continue
+			}
+			if err == nil {
+				err = client.Models.Delete(ctx, name)
+			}
+		default:
+			continue
+		}
+
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "FAILED to %s %s: %v\n", change.Action, name, err)
+			continue
+		}
+		fmt.Printf("%s %s\n", pastTense(change.Action), name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d model changes failed", failed)
+	}
+	return nil
+}
+
+func pastTense(action string) string {
+	switch action {
+	case opperai.ModelActionCreate:
+		return "Created"
+	case opperai.ModelActionUpdate:
+		return "Updated"
+	case opperai.ModelActionDelete:
+		return "Deleted"
+	}
+	return action
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+const defaultBenchPrompt = "Write a short paragraph about the history of computing."
+
+// promptSeparator splits a prompt file into several prompts
+var promptSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+func (c *BenchModelsCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	format := strings.ToLower(c.Format)
+	if format != "" && format != "table" && format != "json" {
+		return fmt.Errorf("unknown format: %s (must be table or json)", c.Format)
+	}
+
+	prompts := []string{defaultBenchPrompt}
+	if c.PromptFile != "" {
+		data, err := readInputFile(c.PromptFile)
+		if err != nil {
+			return fmt.Errorf("error reading prompt file: %w", err)
+		}
+		prompts = splitPrompts(string(data))
+		if len(prompts) == 0 {
+			return fmt.Errorf("no prompts found in %s", c.PromptFile)
+		}
+	}
+
+	var results []*opperai.BenchResult
+	for _, name := range c.Names {
+		done := 0
+		label := truncateString(name, 40)
+		output.Progress(os.Stderr, 0, c.Requests, label)
+		result, err := client.Call.Bench(ctx, opperai.BenchOptions{
+			Model:       name,
+			Prompts:     prompts,
+			Requests:    c.Requests,
+			Concurrency: c.Concurrency,
+			Warmup:      c.Warmup,
+			Timeout:     c.Timeout,
+			OnSample: func(opperai.BenchSample) {
+				done++
+				output.Progress(os.Stderr, done, c.Requests, label)
+			},
+		})
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return fmt.Errorf("error benchmarking %s: %w", name, err)
+		}
+		results = append(results, result)
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printBenchResults(results)
+	}
+
+	for _, r := range results {
+		if r.Succeeded == 0 {
+			return fmt.Errorf("all requests to %s failed", r.Model)
+		}
+	}
+	return nil
+}
+
+// splitPrompts splits text on lines containing only "---", dropping empty prompts
+func splitPrompts(text string) []string {
+	var prompts []string
+	for _, p := range promptSeparator.Split(text, -1) {
+		if p = strings.TrimSpace(p); p != "" {
+			prompts = append(prompts, p)
+		}
+	}
+	return prompts
+}
+
+func printBenchResults(results []*opperai.BenchResult) {
+	ms := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		if r.Succeeded == 0 {
+			rows = append(rows, []string{r.Model, fmt.Sprintf("0/%d", r.Requests), "-", "-", "-", "-", "-", "-"})
+			continue
+		}
+		rows = append(rows, []string{
+			r.Model,
+			fmt.Sprintf("%d/%d (%.0f%%)", r.Succeeded, r.Requests, r.SuccessRate*100),
+			ms(r.TTFT.P50),
+			ms(r.TTFT.P95),
+			ms(r.Latency.P50),
+			ms(r.Latency.P95),
+			ms(r.Latency.P99),
+			fmt.Sprintf("%.1f", r.TokensPerSecond),
+		})
+	}
+	output.Table([]string{"MODEL", "OK", "TTFT P50", "TTFT P95", "P50", "P95", "P99", "TOK/S"}, rows)
+	fmt.Println("\nTimes in milliseconds. TOK/S is estimated from the output length.")
+
+	for _, r := range results {
+		if len(r.Errors) == 0 {
+			continue
+		}
+		msgs := make([]string, 0, len(r.Errors))
+		for msg := range r.Errors {
+			msgs = append(msgs, msg)
+		}
+		sort.Strings(msgs)
+		fmt.Fprintf(os.Stderr, "\nErrors for %s:\n", r.Model)
+		for _, msg := range msgs {
+			fmt.Fprintf(os.Stderr, "  %dx %s\n", r.Errors[msg], truncateString(singleLine(msg), 100))
+		}
+	}
+}
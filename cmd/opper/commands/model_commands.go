@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
 	"github.com/opper-ai/oppercli/opperai"
 )
 
@@ -49,15 +52,35 @@ func (c *ListModelsCommand) Execute(ctx context.Context, client *opperai.Client)
 }
 
 func (c *CreateModelCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	extraJSON := c.Extra
+	if c.ExtraFile != "" {
+		if c.Extra != "" && c.Extra != "{}" {
+			return fmt.Errorf("extra JSON cannot be given both as an argument and with --extra-file")
+		}
+		data, err := readInputFile(c.ExtraFile)
+		if err != nil {
+			return fmt.Errorf("error reading extra file: %w", err)
+		}
+		extraJSON = string(data)
+	}
+	if extraJSON == "" {
+		extraJSON = "{}"
+	}
+
 	var extra map[string]interface{}
-	if err := json.Unmarshal([]byte(c.Extra), &extra); err != nil {
+	if err := json.Unmarshal([]byte(extraJSON), &extra); err != nil {
 		return fmt.Errorf("invalid extra JSON: %w", err)
 	}
 
+	apiKey, err := resolveAPIKey(c.APIKey, c.APIKeyFile, c.APIKeyEnv, fmt.Sprintf("API key for %s: ", c.Identifier))
+	if err != nil {
+		return err
+	}
+
 	model := opperai.CustomLanguageModel{
 		Name:       c.Name,
 		Identifier: c.Identifier,
-		APIKey:     c.APIKey,
+		APIKey:     apiKey,
 		Extra:      extra,
 	}
 
@@ -71,6 +94,49 @@ func (c *CreateModelCommand) Execute(ctx context.Context, client *opperai.Client
 	return nil
 }
 
+func (c *UpdateModelCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	update := opperai.CustomLanguageModel{
+		Name:       c.Rename,
+		Identifier: c.Identifier,
+	}
+
+	if c.Extra != "" && c.ExtraFile != "" {
+		return fmt.Errorf("use only one of --extra and --extra-file")
+	}
+	extraJSON := c.Extra
+	if c.ExtraFile != "" {
+		data, err := readInputFile(c.ExtraFile)
+		if err != nil {
+			return fmt.Errorf("error reading extra file: %w", err)
+		}
+		extraJSON = string(data)
+	}
+	if extraJSON != "" {
+		if err := json.Unmarshal([]byte(extraJSON), &update.Extra); err != nil {
+			return fmt.Errorf("invalid extra JSON: %w", err)
+		}
+	}
+
+	if c.APIKeyFile != "" || c.APIKeyEnv != "" || c.PromptAPIKey {
+		key, err := resolveAPIKey("", c.APIKeyFile, c.APIKeyEnv, fmt.Sprintf("New API key for %s: ", c.Name))
+		if err != nil {
+			return err
+		}
+		update.APIKey = key
+	}
+
+	if update.Name == "" && update.Identifier == "" && update.APIKey == "" && update.Extra == nil {
+		return fmt.Errorf("nothing to update: use --identifier, --rename, --extra, --extra-file or an API key flag")
+	}
+
+	if err := client.Models.Update(ctx, c.Name, update); err != nil {
+		return fmt.Errorf("error updating model: %w", err)
+	}
+
+	fmt.Printf("Successfully updated model: %s\n", c.Name)
+	return nil
+}
+
 func (c *DeleteModelCommand) Execute(ctx context.Context, client *opperai.Client) error {
 	if err := client.Models.Delete(ctx, c.Name); err != nil {
 		return fmt.Errorf("error deleting model: %w", err)
@@ -131,46 +197,164 @@ func (c *TestModelCommand) Execute(ctx context.Context, client *opperai.Client)
 }
 
 func (c *ListBuiltinModelsCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	format := strings.ToLower(c.Format)
+	if format != "" && format != "table" && format != "json" {
+		return fmt.Errorf("unknown format: %s (must be table or json)", c.Format)
+	}
+
 	models, err := client.Models.ListBuiltin(ctx)
 	if err != nil {
 		return fmt.Errorf("error listing built-in models: %w", err)
 	}
 
-	// Find the longest name and provider for padding
-	maxNameLen := 4     // minimum length for "NAME"
-	maxProviderLen := 8 // minimum length for "PROVIDER"
-	for _, model := range models {
-		// Only consider models that match the filter
-		if c.Filter != "" && !strings.Contains(strings.ToLower(model.Name), strings.ToLower(c.Filter)) {
+	models = opperai.FilterBuiltinModels(models, opperai.BuiltinModelFilter{
+		Query:             c.Filter,
+		Providers:         c.Providers,
+		Locations:         c.Locations,
+		Capabilities:      c.Capabilities,
+		MaxInputCost:      c.MaxInputPrice / tokensPerMillion,
+		MaxOutputCost:     c.MaxOutputPrice / tokensPerMillion,
+		MinContextWindow:  c.MinContext,
+		ExcludeDeprecated: c.ExcludeDeprecated,
+	})
+	if err := opperai.SortBuiltinModels(models, c.Sort, c.Reverse); err != nil {
+		return err
+	}
+
+	if format == "json" {
+		if models == nil {
+			models = []opperai.BuiltinLanguageModel{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(models)
+	}
+
+	if len(models) == 0 {
+		fmt.Println("No built-in models match")
+		return nil
+	}
+
+	rows := make([][]string, 0, len(models))
+	deprecated := 0
+	for _, m := range models {
+		name := m.Name
+		if m.Deprecated {
+			name += " (deprecated)"
+			deprecated++
+		}
+		rows = append(rows, []string{
+			name,
+			m.HostingProvider,
+			m.Location,
+			formatTokenCount(m.ContextWindow),
+			formatPricePerMillion(m.InputCostPerToken),
+			formatPricePerMillion(m.OutputCostPerToken),
+			strings.Join(builtinFeatures(m), ","),
+		})
+	}
+	output.Table([]string{"NAME", "PROVIDER", "LOCATION", "CONTEXT", "INPUT $/1M", "OUTPUT $/1M", "CAPABILITIES"}, rows)
+
+	fmt.Printf("\n%d models", len(models))
+	if deprecated > 0 {
+		fmt.Printf(", %d deprecated (use --exclude-deprecated to hide)", deprecated)
+	}
+	fmt.Println()
+	return nil
+}
+
+const tokensPerMillion = 1_000_000
+
+// formatTokenCount returns a short token count such as "128k", or "-" if unknown
+func formatTokenCount(n int) string {
+	switch {
+	case n <= 0:
+		return "-"
+	case n >= 1_000_000 && n%1_000_000 == 0:
+		return fmt.Sprintf("%dM", n/1_000_000)
+	case n >= 1000:
+		return fmt.Sprintf("%dk", n/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// formatPricePerMillion returns a per-token cost in USD per million tokens
+func formatPricePerMillion(costPerToken float64) string {
+	if costPerToken <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.4g", costPerToken*tokensPerMillion)
+}
+
+// builtinFeatures lists a model's capabilities followed by its non-text
+// input modalities, such as "image"
+func builtinFeatures(m opperai.BuiltinLanguageModel) []string {
+	features := append([]string(nil), m.Capabilities...)
+	for _, modality := range m.InputModalities {
+		if strings.EqualFold(modality, "text") {
 			continue
 		}
-		if len(model.Name) > maxNameLen {
-			maxNameLen = len(model.Name)
+		seen := false
+		for _, f := range features {
+			seen = seen || strings.EqualFold(f, modality)
 		}
-		if len(model.HostingProvider) > maxProviderLen {
-			maxProviderLen = len(model.HostingProvider)
+		if !seen {
+			features = append(features, modality)
 		}
 	}
+	return features
+}
 
-	// Print header
-	fmt.Printf("\n%-*s  %-*s  %s\n", maxNameLen, "NAME", maxProviderLen, "PROVIDER", "LOCATION")
-	fmt.Printf("%s  %s  %s\n",
-		strings.Repeat("─", maxNameLen),
-		strings.Repeat("─", maxProviderLen),
-		strings.Repeat("─", 8))
+// resolveAPIKey returns the key given directly, read from a file ("-" for
+// stdin) or an environment variable, or prompted for without echo when none
+// of those is set. At most one source may be used.
+func resolveAPIKey(key, file, env, prompt string) (string, error) {
+	sources := 0
+	for _, s := range []string{key, file, env} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return "", fmt.Errorf("use only one of the api-key argument, --api-key-file and --api-key-env")
+	}
 
-	for _, model := range models {
-		// Only print models that match the filter
-		if c.Filter != "" && !strings.Contains(strings.ToLower(model.Name), strings.ToLower(c.Filter)) {
-			continue
+	switch {
+	case key != "":
+		return key, nil
+	case file != "":
+		data, err := readInputFile(file)
+		if err != nil {
+			return "", fmt.Errorf("error reading API key file: %w", err)
+		}
+		key = strings.TrimSpace(string(data))
+		if key == "" {
+			return "", fmt.Errorf("API key file %s is empty", file)
+		}
+		return key, nil
+	case env != "":
+		key = strings.TrimSpace(os.Getenv(env))
+		if key == "" {
+			return "", fmt.Errorf("environment variable %s is not set", env)
 		}
-		fmt.Printf("%-*s  %-*s  %s\n",
-			maxNameLen,
-			model.Name,
-			maxProviderLen,
-			model.HostingProvider,
-			model.Location)
+		return key, nil
 	}
-	fmt.Println()
-	return nil
+
+	key, err := promptSecret(prompt)
+	if err != nil {
+		return "", fmt.Errorf("%w; use --api-key-file or --api-key-env", err)
+	}
+	if key == "" {
+		return "", fmt.Errorf("no API key entered")
+	}
+	return key, nil
+}
+
+// readInputFile reads a file, or stdin for "-"
+func readInputFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
 }
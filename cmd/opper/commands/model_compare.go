@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/opperai"
+	"github.com/opper-ai/oppercli/opperai/localeval"
+	"golang.org/x/term"
+)
+
+const defaultOutputWidth = 120
+
+func (c *CompareModelsCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	format := strings.ToLower(c.Format)
+	if format != "" && format != "table" && format != "json" {
+		return fmt.Errorf("unknown format: %s (must be table or json)", c.Format)
+	}
+
+	var models []string
+	for _, model := range c.Models {
+		if model = strings.TrimSpace(model); model != "" {
+			models = append(models, model)
+		}
+	}
+	if len(models) < 2 {
+		return fmt.Errorf("at least two models are required")
+	}
+
+	var cases []localeval.Case
+	switch {
+	case c.InputFile != "" && c.Input != "":
+		return fmt.Errorf("use either an input or --input-file, not both")
+	case c.InputFile != "":
+		data, err := readInputFile(c.InputFile)
+		if err != nil {
+			return fmt.Errorf("error reading input file: %w", err)
+		}
+		if cases, err = localeval.ReadCases(bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("invalid input file %s: %w", c.InputFile, err)
+		}
+		if len(cases) == 0 {
+			return fmt.Errorf("no cases found in %s", c.InputFile)
+		}
+	case c.Input != "":
+		cases = []localeval.Case{{Input: c.Input}}
+	default:
+		return fmt.Errorf("an input or --input-file is required")
+	}
+
+	total := len(cases) * len(models)
+	output.Progress(os.Stderr, 0, total, "")
+	comparison, err := localeval.Compare(ctx, client.Call, cases, models, &localeval.CompareOptions{
+		Name:         c.Name,
+		Instructions: c.Instructions,
+		Concurrency:  c.Concurrency,
+		Timeout:      c.Timeout,
+		JudgeModel:   c.Judge,
+		Criteria:     c.Criteria,
+		OnOutput: func(done, total int, o localeval.ModelOutput) {
+			output.Progress(os.Stderr, done, total, truncateString(o.Model, 40))
+		},
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(comparison); err != nil {
+			return err
+		}
+	} else {
+		printModelComparison(comparison)
+	}
+
+	for _, s := range comparison.Summary {
+		if s.Succeeded == 0 {
+			return fmt.Errorf("all calls to %s failed", s.Model)
+		}
+	}
+	return nil
+}
+
+func printModelComparison(comparison *localeval.Comparison) {
+	width := defaultOutputWidth
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		width = w
+	}
+
+	for i, cc := range comparison.Cases {
+		id := cc.Case.ID
+		if id == "" {
+			id = fmt.Sprintf("case-%d", i+1)
+		}
+		fmt.Printf("%s: %s\n\n", id, truncateString(singleLine(cc.Case.Input), max(width-len(id)-2, 10)))
+
+		headers := make([]string, len(cc.Outputs))
+		texts := make([]string, len(cc.Outputs))
+		for j, o := range cc.Outputs {
+			headers[j] = fmt.Sprintf("%s (%.0fms", o.Model, o.LatencyMs)
+			if o.Cost > 0 {
+				headers[j] += fmt.Sprintf(", $%.4f", o.Cost)
+			}
+			if o.Rank > 0 {
+				headers[j] += fmt.Sprintf(", #%d", o.Rank)
+			}
+			headers[j] += ")"
+			texts[j] = o.Output
+			if o.Error != "" {
+				texts[j] = "ERROR: " + o.Error
+			}
+		}
+		output.Columns(os.Stdout, width, headers, texts)
+
+		if cc.JudgeComment != "" {
+			fmt.Printf("\nJudge: %s\n", cc.JudgeComment)
+		}
+		if cc.JudgeError != "" {
+			fmt.Fprintf(os.Stderr, "\nJudge failed for %s: %s\n", id, cc.JudgeError)
+		}
+		fmt.Println()
+	}
+
+	headers := []string{"MODEL", "OK", "AVG LATENCY", "MAX LATENCY", "TOKENS", "COST"}
+	if comparison.Judge != "" {
+		headers = append(headers, "WINS", "AVG RANK")
+	}
+	rows := make([][]string, 0, len(comparison.Summary))
+	for _, s := range comparison.Summary {
+		ok := fmt.Sprintf("%d/%d", s.Succeeded, s.Succeeded+s.Failed)
+		row := []string{s.Model, ok, "-", "-", "-", "-"}
+		if s.Succeeded > 0 {
+			row = []string{
+				s.Model,
+				ok,
+				fmt.Sprintf("%.0fms", s.Latency.Avg),
+				fmt.Sprintf("%.0fms", s.Latency.Max),
+				fmt.Sprintf("%d", s.Tokens),
+				fmt.Sprintf("$%.4f", s.Cost),
+			}
+		}
+		if comparison.Judge != "" {
+			avgRank := "-"
+			if s.AvgRank > 0 {
+				avgRank = fmt.Sprintf("%.2f", s.AvgRank)
+			}
+			row = append(row, fmt.Sprintf("%d", s.Wins), avgRank)
+		}
+		rows = append(rows, row)
+	}
+	output.Table(headers, rows)
+	if comparison.Judge != "" {
+		fmt.Printf("\nRanked by %s\n", comparison.Judge)
+	}
+}
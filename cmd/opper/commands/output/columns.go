@@ -0,0 +1,95 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Columns prints texts side by side in columns of equal width that fill
+// width characters, wrapping lines at spaces, e.g.
+//
+//	model-a                 │ model-b
+//	────────────────────────┼────────────────────────
+//	The capital of Sweden   │ Stockholm.
+//	is Stockholm.           │
+func Columns(w io.Writer, width int, headers, texts []string) {
+	if len(headers) == 0 {
+		return
+	}
+	colWidth := (width - 3*(len(headers)-1)) / len(headers)
+	if colWidth < 10 {
+		colWidth = 10
+	}
+
+	wrapped := make([][]string, len(texts))
+	rows := 0
+	for i, text := range texts {
+		wrapped[i] = wrap(text, colWidth)
+		rows = max(rows, len(wrapped[i]))
+	}
+
+	printRow := func(cells func(i int) string) {
+		parts := make([]string, len(headers))
+		for i := range headers {
+			cell := cells(i)
+			parts[i] = cell + strings.Repeat(" ", max(0, colWidth-utf8.RuneCountInString(cell)))
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(parts, " │ "), " "))
+	}
+
+	printRow(func(i int) string { return cutLine(headers[i], colWidth) })
+	seps := make([]string, len(headers))
+	for i := range seps {
+		seps[i] = strings.Repeat("─", colWidth)
+	}
+	fmt.Fprintln(w, strings.Join(seps, "─┼─"))
+
+	for row := 0; row < rows; row++ {
+		printRow(func(i int) string {
+			if i < len(wrapped) && row < len(wrapped[i]) {
+				return wrapped[i][row]
+			}
+			return ""
+		})
+	}
+}
+
+// wrap splits text into lines of at most width characters, breaking at
+// spaces where possible and keeping existing line breaks
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\t", "    "), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				lines = append(lines, cutLine(word, width))
+				word = string([]rune(word)[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// cutLine cuts s to at most width characters
+func cutLine(s string, width int) string {
+	if r := []rune(s); len(r) > width {
+		return string(r[:width])
+	}
+	return s
+}
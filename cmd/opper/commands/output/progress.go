@@ -0,0 +1,24 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const progressWidth = 30
+
+// Progress redraws a single-line progress bar, e.g.
+//
+//	[###########-------------------] 12/32 uploading docs/guide.md
+//
+// Callers print a newline once they are done.
+func Progress(w io.Writer, done, total int, label string) {
+	filled := progressWidth
+	if total > 0 {
+		filled = done * progressWidth / total
+	}
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", progressWidth-filled)
+	// Clear to the end of the line so a shorter label overwrites a longer one
+	fmt.Fprintf(w, "\r[%s] %d/%d %s\033[K", bar, done, total, label)
+}
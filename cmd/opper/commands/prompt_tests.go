@@ -0,0 +1,528 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/opper-ai/oppercli/cmd/opper/commands/output"
+	"github.com/opper-ai/oppercli/opperai"
+	"gopkg.in/yaml.v3"
+)
+
+// Prompt tests live next to the prompt they exercise:
+//
+//	prompts/greeting.prompt                  instructions, with optional YAML front matter
+//	prompts/greeting.tests.yaml              test cases
+//	prompts/testdata/greeting/<case>.golden  snapshots written by --update
+const (
+	promptExt    = ".prompt"
+	testsExt     = ".tests.yaml"
+	testsExtAlt  = ".tests.yml"
+	snapshotDir  = "testdata"
+	snapshotExt  = ".golden"
+	frontMatter  = "---"
+	maxDiagLines = 40
+)
+
+// promptFile is a prompt and its optional front matter
+type promptFile struct {
+	Name         string `yaml:"name"`
+	Model        string `yaml:"model"`
+	Instructions string `yaml:"-"`
+}
+
+// promptTestFile is the contents of a *.tests.yaml file
+type promptTestFile struct {
+	Prompt string           `yaml:"prompt"`
+	Model  string           `yaml:"model"`
+	Tests  []promptTestCase `yaml:"tests"`
+}
+
+// promptTestCase is a single input and the assertions made on its output
+type promptTestCase struct {
+	Name        string      `yaml:"name"`
+	Input       interface{} `yaml:"input"`
+	Expected    *string     `yaml:"expected"`
+	Snapshot    bool        `yaml:"snapshot"`
+	Contains    stringList  `yaml:"contains"`
+	NotContains stringList  `yaml:"not_contains"`
+	Regex       stringList  `yaml:"regex"`
+	MaxLength   int         `yaml:"max_length"`
+	JSONSchema  interface{} `yaml:"json_schema"`
+}
+
+// stringList accepts either a single string or a list of strings
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = stringList{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// promptTestSuite is a test file resolved against its prompt
+type promptTestSuite struct {
+	Path   string
+	Dir    string
+	Prompt promptFile
+	Model  string
+	Tests  []promptTestCase
+}
+
+// promptTestResult is the outcome of one test case
+type promptTestResult struct {
+	Suite    string
+	Name     string
+	Failures []string
+	Output   string
+	Notes    []string
+	Duration time.Duration
+}
+
+func (r promptTestResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+func (c *PromptTestCommand) Execute(ctx context.Context, client *opperai.Client) error {
+	var filter *regexp.Regexp
+	if c.Run != "" {
+		var err error
+		if filter, err = regexp.Compile(c.Run); err != nil {
+			return fmt.Errorf("invalid --run pattern: %w", err)
+		}
+	}
+
+	paths := c.Paths
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	suites, err := discoverPromptTests(paths)
+	if err != nil {
+		return err
+	}
+	if len(suites) == 0 {
+		return fmt.Errorf("no *%s files found", testsExt)
+	}
+
+	type job struct {
+		suite *promptTestSuite
+		test  promptTestCase
+	}
+	var jobs []job
+	for _, suite := range suites {
+		for _, test := range suite.Tests {
+			if filter != nil && !filter.MatchString(suite.Path+"/"+test.Name) {
+				continue
+			}
+			jobs = append(jobs, job{suite, test})
+		}
+	}
+	if len(jobs) == 0 {
+		if filter == nil {
+			return fmt.Errorf("no tests found")
+		}
+		return fmt.Errorf("no tests match %q", c.Run)
+	}
+
+	parallel := c.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	results := make([]promptTestResult, len(jobs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallel)
+	for i, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, j job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = c.runPromptTest(ctx, client, j.suite, j.test)
+		}(i, j)
+	}
+	wg.Wait()
+
+	out := io.Writer(os.Stdout)
+	if c.OutPath != "" {
+		f, err := os.Create(c.OutPath)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch strings.ToLower(c.Format) {
+	case "", "tap":
+		err = writeTAP(out, results)
+	case "junit":
+		err = writeJUnit(out, results)
+	default:
+		return fmt.Errorf("unsupported format: %s (must be tap or junit)", c.Format)
+	}
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d prompt tests failed", failed, len(results))
+	}
+	return nil
+}
+
+func (c *PromptTestCommand) runPromptTest(ctx context.Context, client *opperai.Client, suite *promptTestSuite, test promptTestCase) promptTestResult {
+	result := promptTestResult{Suite: suite.Path, Name: test.Name}
+
+	input, err := promptTestInput(test.Input)
+	if err != nil {
+		result.Failures = append(result.Failures, err.Error())
+		return result
+	}
+
+	model := suite.Model
+	if c.Model != "" {
+		model = c.Model
+	}
+
+	start := time.Now()
+	response, err := client.Call.Call(ctx, suite.Prompt.Name, suite.Prompt.Instructions, input, model, false, nil)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Failures = append(result.Failures, fmt.Sprintf("call failed: %v", err))
+		return result
+	}
+	result.Output = response.Message
+
+	result.Failures = append(result.Failures, checkAssertions(suite, test, result.Output)...)
+
+	if test.Snapshot {
+		path := snapshotPath(suite, test.Name)
+		want, err := os.ReadFile(path)
+		switch {
+		case c.Update:
+			if err != nil || string(want) != result.Output {
+				if err := writeSnapshot(path, result.Output); err != nil {
+					result.Failures = append(result.Failures, err.Error())
+				} else {
+					result.Notes = append(result.Notes, "snapshot updated: "+path)
+				}
+			}
+		case os.IsNotExist(err):
+			result.Failures = append(result.Failures, fmt.Sprintf("snapshot %s does not exist, run with --update to create it", path))
+		case err != nil:
+			result.Failures = append(result.Failures, fmt.Sprintf("error reading snapshot: %v", err))
+		case string(want) != result.Output:
+			result.Failures = append(result.Failures, "output does not match snapshot\n"+
+				output.UnifiedDiff(path, "output", string(want), result.Output))
+		}
+	}
+
+	return result
+}
+
+// checkAssertions returns a description of every assertion the output fails
+func checkAssertions(suite *promptTestSuite, test promptTestCase, out string) []string {
+	var failures []string
+
+	if test.Expected != nil && strings.TrimSpace(*test.Expected) != strings.TrimSpace(out) {
+		failures = append(failures, "output does not match expected\n"+
+			output.UnifiedDiff("expected", "output", strings.TrimSpace(*test.Expected)+"\n", strings.TrimSpace(out)+"\n"))
+	}
+
+	for _, s := range test.Contains {
+		if !strings.Contains(out, s) {
+			failures = append(failures, fmt.Sprintf("output does not contain %q", s))
+		}
+	}
+
+	for _, s := range test.NotContains {
+		if strings.Contains(out, s) {
+			failures = append(failures, fmt.Sprintf("output contains %q", s))
+		}
+	}
+
+	for _, pattern := range test.Regex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("invalid regex %q: %v", pattern, err))
+			continue
+		}
+		if !re.MatchString(out) {
+			failures = append(failures, fmt.Sprintf("output does not match regex %q", pattern))
+		}
+	}
+
+	if test.MaxLength > 0 {
+		if n := utf8.RuneCountInString(out); n > test.MaxLength {
+			failures = append(failures, fmt.Sprintf("output is %d characters, max length is %d", n, test.MaxLength))
+		}
+	}
+
+	if test.JSONSchema != nil {
+		schema, err := loadJSONSchema(suite.Dir, test.JSONSchema)
+		if err != nil {
+			return append(failures, err.Error())
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(opperai.StripCodeFence(out)), &value); err != nil {
+			return append(failures, fmt.Sprintf("output is not valid JSON: %v", err))
+		}
+		for _, violation := range validateJSONSchema(schema, value, "$") {
+			failures = append(failures, "json schema: "+violation)
+		}
+	}
+
+	return failures
+}
+
+// discoverPromptTests finds test files under the given paths and loads them
+// together with their prompts
+func discoverPromptTests(paths []string) ([]*promptTestSuite, error) {
+	var files []string
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, root)
+			continue
+		}
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != root && (strings.HasPrefix(name, ".") || name == "node_modules" || name == snapshotDir) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, testsExt) || strings.HasSuffix(path, testsExtAlt) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+
+	suites := make([]*promptTestSuite, 0, len(files))
+	for _, path := range files {
+		suite, err := loadPromptTestSuite(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		suites = append(suites, suite)
+	}
+	return suites, nil
+}
+
+func loadPromptTestSuite(path string) (*promptTestSuite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file promptTestFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid test file: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	base := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), testsExt), testsExtAlt)
+
+	promptPath := filepath.Join(dir, base+promptExt)
+	if file.Prompt != "" {
+		promptPath = filepath.Join(dir, file.Prompt)
+	}
+	prompt, err := loadPromptFile(promptPath)
+	if err != nil {
+		return nil, err
+	}
+	if prompt.Name == "" {
+		prompt.Name = base
+	}
+
+	seen := map[string]bool{}
+	for i, test := range file.Tests {
+		if test.Name == "" {
+			file.Tests[i].Name = fmt.Sprintf("case %d", i+1)
+		}
+		if seen[file.Tests[i].Name] {
+			return nil, fmt.Errorf("duplicate test name %q", file.Tests[i].Name)
+		}
+		seen[file.Tests[i].Name] = true
+	}
+	if err := checkSnapshotNames(file.Tests); err != nil {
+		return nil, err
+	}
+
+	model := prompt.Model
+	if file.Model != "" {
+		model = file.Model
+	}
+
+	return &promptTestSuite{
+		Path:   filepath.ToSlash(path),
+		Dir:    dir,
+		Prompt: *prompt,
+		Model:  model,
+		Tests:  file.Tests,
+	}, nil
+}
+
+// loadPromptFile reads a prompt, splitting off YAML front matter if present
+func loadPromptFile(path string) (*promptFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading prompt: %w", err)
+	}
+
+	prompt := &promptFile{}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if strings.HasPrefix(text, frontMatter+"\n") {
+		rest := text[len(frontMatter)+1:]
+		end := strings.Index(rest, "\n"+frontMatter+"\n")
+		if end < 0 {
+			return nil, fmt.Errorf("%s: unterminated front matter", path)
+		}
+		if err := yaml.Unmarshal([]byte(rest[:end]), prompt); err != nil {
+			return nil, fmt.Errorf("%s: invalid front matter: %w", path, err)
+		}
+		text = rest[end+len(frontMatter)+2:]
+	}
+
+	prompt.Instructions = strings.TrimSpace(text)
+	if prompt.Instructions == "" {
+		return nil, fmt.Errorf("%s: prompt is empty", path)
+	}
+	return prompt, nil
+}
+
+// promptTestInput returns string inputs as-is and encodes anything else as JSON
+func promptTestInput(input interface{}) (string, error) {
+	switch v := input.(type) {
+	case nil:
+		return "", fmt.Errorf("input is required")
+	case string:
+		return v, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("error encoding input: %w", err)
+		}
+		return string(data), nil
+	}
+}
+
+var snapshotNameRe = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// snapshotName turns a test name into a file name. Different test names can
+// map to the same file, see checkSnapshotNames.
+func snapshotName(testName string) string {
+	return strings.Trim(snapshotNameRe.ReplaceAllString(strings.ToLower(testName), "_"), "_")
+}
+
+func snapshotPath(suite *promptTestSuite, testName string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(suite.Path), testsExt), testsExtAlt)
+	return filepath.Join(suite.Dir, snapshotDir, base, snapshotName(testName)+snapshotExt)
+}
+
+// checkSnapshotNames rejects snapshot tests whose names give an empty file
+// name or the same file as another test
+func checkSnapshotNames(tests []promptTestCase) error {
+	owners := map[string]string{}
+	for _, test := range tests {
+		if !test.Snapshot {
+			continue
+		}
+		name := snapshotName(test.Name)
+		if name == "" {
+			return fmt.Errorf("test %q needs a name with letters or digits to use a snapshot", test.Name)
+		}
+		if other, ok := owners[name]; ok {
+			return fmt.Errorf("tests %q and %q would share the snapshot file %s%s, rename one of them", other, test.Name, name, snapshotExt)
+		}
+		owners[name] = test.Name
+	}
+	return nil
+}
+
+func writeSnapshot(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("error writing snapshot: %w", err)
+	}
+	return nil
+}
+
+// loadJSONSchema accepts an inline schema or a path to a JSON or YAML file
+// relative to the test file
+func loadJSONSchema(dir string, schema interface{}) (map[string]interface{}, error) {
+	if path, ok := schema.(string); ok {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			return nil, fmt.Errorf("error reading json schema: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("invalid json schema %s: %w", path, err)
+		}
+	}
+
+	// Round-trip through JSON so YAML and JSON schemas share one representation
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json schema: %w", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("json schema must be an object")
+	}
+	return result, nil
+}
+
+// lastLines keeps the tail of long outputs readable in reports
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) <= n {
+		return strings.Join(lines, "\n")
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "... (%d lines omitted)\n", len(lines)-n)
+	buf.WriteString(strings.Join(lines[len(lines)-n:], "\n"))
+	return buf.String()
+}
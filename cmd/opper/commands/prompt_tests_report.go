@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeTAP reports results in TAP version 13, with failures and output in a
+// YAML diagnostic block
+func writeTAP(w io.Writer, results []promptTestResult) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "TAP version 13\n1..%d\n", len(results))
+
+	for i, r := range results {
+		status := "ok"
+		if !r.Passed() {
+			status = "not ok"
+		}
+		fmt.Fprintf(&sb, "%s %d - %s: %s\n", status, i+1, r.Suite, r.Name)
+		for _, note := range r.Notes {
+			fmt.Fprintf(&sb, "# %s\n", note)
+		}
+		if r.Passed() {
+			continue
+		}
+
+		diag := map[string]interface{}{
+			"failures":    r.Failures,
+			"duration_ms": r.Duration.Milliseconds(),
+		}
+		if r.Output != "" {
+			diag["output"] = lastLines(r.Output, maxDiagLines)
+		}
+		var data strings.Builder
+		enc := yaml.NewEncoder(&data)
+		enc.SetIndent(2)
+		if err := enc.Encode(diag); err != nil {
+			return err
+		}
+		sb.WriteString("  ---\n")
+		for _, line := range strings.Split(strings.TrimRight(data.String(), "\n"), "\n") {
+			fmt.Fprintf(&sb, "  %s\n", line)
+		}
+		sb.WriteString("  ...\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// writeJUnit reports results as JUnit XML with one test suite per test file
+func writeJUnit(w io.Writer, results []promptTestResult) error {
+	report := junitTestSuites{}
+	index := map[string]int{}
+	var total float64
+
+	for _, r := range results {
+		i, ok := index[r.Suite]
+		if !ok {
+			i = len(report.Suites)
+			index[r.Suite] = i
+			report.Suites = append(report.Suites, junitTestSuite{Name: r.Suite})
+		}
+		suite := &report.Suites[i]
+
+		seconds := r.Duration.Seconds()
+		tc := junitTestCase{
+			Name:      r.Name,
+			ClassName: r.Suite,
+			Time:      fmt.Sprintf("%.3f", seconds),
+			SystemOut: r.Output,
+		}
+		if !r.Passed() {
+			tc.Failure = &junitFailure{
+				Message: strings.SplitN(r.Failures[0], "\n", 2)[0],
+				Body:    strings.Join(r.Failures, "\n\n"),
+			}
+			suite.Failures++
+			report.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+		report.Tests++
+		total += seconds
+	}
+
+	for i := range report.Suites {
+		var seconds float64
+		for _, r := range results {
+			if r.Suite == report.Suites[i].Name {
+				seconds += r.Duration.Seconds()
+			}
+		}
+		report.Suites[i].Time = fmt.Sprintf("%.3f", seconds)
+	}
+	report.Time = fmt.Sprintf("%.3f", total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPromptFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    promptFile
+		wantErr string
+	}{
+		{
+			name:    "no front matter",
+			content: "\nSummarize the text.\n",
+			want:    promptFile{Instructions: "Summarize the text."},
+		},
+		{
+			name:    "front matter",
+			content: "---\nname: summarize\nmodel: openai/gpt-4o\n---\nSummarize the text.\n",
+			want:    promptFile{Name: "summarize", Model: "openai/gpt-4o", Instructions: "Summarize the text."},
+		},
+		{
+			name:    "windows line endings",
+			content: "---\r\nname: summarize\r\n---\r\nSummarize.\r\n",
+			want:    promptFile{Name: "summarize", Instructions: "Summarize."},
+		},
+		{
+			name:    "separator later in the body is kept",
+			content: "Part one\n---\nPart two",
+			want:    promptFile{Instructions: "Part one\n---\nPart two"},
+		},
+		{
+			name:    "unterminated front matter",
+			content: "---\nname: summarize\nSummarize.\n",
+			wantErr: "unterminated front matter",
+		},
+		{
+			name:    "invalid front matter",
+			content: "---\nname: [\n---\nSummarize.\n",
+			wantErr: "invalid front matter",
+		},
+		{
+			name:    "empty prompt",
+			content: "---\nname: summarize\n---\n\n",
+			wantErr: "prompt is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.prompt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := loadPromptFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadPromptFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadPromptFile() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("loadPromptFile() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotPath(t *testing.T) {
+	suite := &promptTestSuite{Path: "prompts/summarize.tests.yaml", Dir: "prompts"}
+
+	tests := []struct {
+		name     string
+		testName string
+		want     string
+	}{
+		{name: "lowercases and replaces spaces", testName: "Case 1", want: "case_1"},
+		{name: "keeps dashes", testName: "case-1", want: "case-1"},
+		{name: "collapses and trims punctuation", testName: "  What's up?! ", want: "what_s_up"},
+		{name: "non ascii", testName: "résumé", want: "r_sum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := filepath.Join("prompts", snapshotDir, "summarize", tt.want+snapshotExt)
+			if got := snapshotPath(suite, tt.testName); got != want {
+				t.Errorf("snapshotPath() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCheckSnapshotNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		tests   []promptTestCase
+		wantErr string
+	}{
+		{
+			name:  "distinct names",
+			tests: []promptTestCase{{Name: "Case 1", Snapshot: true}, {Name: "case-1", Snapshot: true}},
+		},
+		{
+			name:    "names sharing a file",
+			tests:   []promptTestCase{{Name: "Case 1", Snapshot: true}, {Name: "case_1", Snapshot: true}},
+			wantErr: `tests "Case 1" and "case_1" would share the snapshot file case_1.golden`,
+		},
+		{
+			name:    "name without letters or digits",
+			tests:   []promptTestCase{{Name: "!!!", Snapshot: true}},
+			wantErr: `test "!!!" needs a name with letters or digits`,
+		},
+		{
+			name:  "only snapshot tests are checked",
+			tests: []promptTestCase{{Name: "!!!"}, {Name: "Case 1"}, {Name: "case_1", Snapshot: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSnapshotNames(tt.tests)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkSnapshotNames() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkSnapshotNames() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadPromptTestSuite(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "greet.prompt"), []byte("---\nmodel: openai/gpt-4o\n---\nSay hello.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "default names", content: "tests:\n  - input: a\n  - input: b\n"},
+		{name: "duplicate names", content: "tests:\n  - name: x\n  - name: x\n", wantErr: `duplicate test name "x"`},
+		{name: "snapshot collision", content: "tests:\n  - name: A b\n    snapshot: true\n  - name: a_b\n    snapshot: true\n", wantErr: "would share the snapshot file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "greet.tests.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			suite, err := loadPromptTestSuite(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadPromptTestSuite() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadPromptTestSuite() error = %v", err)
+			}
+			if suite.Prompt.Name != "greet" || suite.Model != "openai/gpt-4o" {
+				t.Errorf("got prompt name %q and model %q", suite.Prompt.Name, suite.Model)
+			}
+			if len(suite.Tests) != 2 || suite.Tests[0].Name != "case 1" || suite.Tests[1].Name != "case 2" {
+				t.Errorf("got tests %+v", suite.Tests)
+			}
+		})
+	}
+}
@@ -10,7 +10,9 @@ import (
 )
 
 type ListTracesCommand struct {
-	Live bool
+	Live     bool
+	Limit    int
+	PageSize int
 }
 
 func (c *ListTracesCommand) Execute(ctx context.Context, client *opperai.Client) error {
@@ -21,7 +23,10 @@ func (c *ListTracesCommand) Execute(ctx context.Context, client *opperai.Client)
 }
 
 func (c *ListTracesCommand) executeOnce(ctx context.Context, client *opperai.Client) error {
-	traces, err := client.Traces.List(ctx, 0)
+	traces, err := client.Traces.Iter(ctx, &opperai.ListOptions{
+		Limit:    c.Limit,
+		PageSize: c.PageSize,
+	}).All()
 	if err != nil {
 		return fmt.Errorf("error listing traces: %w", err)
 	}
@@ -70,7 +75,10 @@ func (c *ListTracesCommand) executeLive(ctx context.Context, client *opperai.Cli
 	}()
 
 	// First, get initial traces and display them in reverse order
-	traces, err := client.Traces.List(ctx, 10)
+	traces, err := client.Traces.Iter(ctx, &opperai.ListOptions{
+		Limit:    c.Limit,
+		PageSize: c.PageSize,
+	}).All()
 	if err != nil {
 		return fmt.Errorf("error listing traces: %w", err)
 	}
@@ -111,7 +119,12 @@ func (c *ListTracesCommand) executeLive(ctx context.Context, client *opperai.Cli
 	}
 
 	// Start watching for updates
-	updates, err := client.Traces.WatchList(pollCtx, seenTraces) // Pass seenTraces to WatchList
+	// Each poll checks one page of the most recent traces
+	pageSize := c.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	updates, err := client.Traces.WatchListN(pollCtx, seenTraces, pageSize) // Pass seenTraces to WatchList
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"time"
 
 	"github.com/opper-ai/oppercli/opperai"
 )
@@ -32,12 +33,24 @@ type DeleteIndexCommand struct {
 
 type GetIndexCommand struct {
 	Name string
+	// Status and Match filter the listed files by index status and glob
+	Status  []string
+	Match   string
+	Sort    string
+	Reverse bool
+	Limit   int
+	// Watch refreshes the output every Interval until indexing completes
+	Watch    bool
+	Interval time.Duration
 }
 
 type QueryIndexCommand struct {
-	Name   string
-	Query  string
-	Filter string
+	Name    string
+	Query   string
+	Filters []string
+	TopK    int
+	Format  string
+	Columns []string
 }
 
 type AddToIndexCommand struct {
@@ -45,11 +58,96 @@ type AddToIndexCommand struct {
 	Key      string
 	Content  string
 	Metadata string
+	From     string
+	Batch    int
+
+	// Chunk is a local file or directory to split into chunks
+	Chunk        string
+	Strategy     string
+	ChunkSize    int
+	ChunkOverlap int
+	Recursive    bool
+	Include      []string
+	Exclude      []string
+	Replace      bool
+	DryRun       bool
 }
 
-type UploadToIndexCommand struct {
+type ExportIndexCommand struct {
+	Name   string
+	Output string
+}
+
+type ImportIndexCommand struct {
+	Name  string
+	File  string
+	Batch int
+}
+
+type CopyIndexCommand struct {
+	Source string
+	Dest   string
+	// DestKey is the config key of the account to copy to, empty for the
+	// same account as the source
+	DestKey string
+	Batch   int
+}
+
+type EvalIndexCommand struct {
+	Name        string
+	Queries     string
+	K           int
+	Concurrency int
+	Format      string
+	// Baseline is a JSON result of an earlier run to compare against
+	Baseline string
+}
+
+type ListIndexDocumentsCommand struct {
 	Name     string
-	FilePath string
+	Limit    int
+	PageSize int
+	Format   string
+}
+
+type GetIndexDocumentCommand struct {
+	Name   string
+	Key    string
+	Format string
+}
+
+type DeleteIndexDocumentsCommand struct {
+	Name    string
+	Key     string
+	Filters []string
+}
+
+type SyncIndexCommand struct {
+	Name        string
+	Dir         string
+	Include     []string
+	Exclude     []string
+	Delete      bool
+	DryRun      bool
+	Manifest    string
+	Concurrency int
+	Retries     int
+	Wait        bool
+	Timeout     time.Duration
+	Interval    time.Duration
+}
+
+type UploadToIndexCommand struct {
+	Name        string
+	FilePath    string
+	Recursive   bool
+	Include     []string
+	Exclude     []string
+	Concurrency int
+	Retries     int
+	Wait        bool
+	Timeout     time.Duration
+	Interval    time.Duration
 }
 
 // Model Commands
@@ -62,6 +160,33 @@ type CreateModelCommand struct {
 	Identifier string
 	APIKey     string
 	Extra      string
+	// APIKeyFile and APIKeyEnv read the key from a file ("-" for stdin) or
+	// environment variable instead of the command line. Without any key
+	// source the key is prompted for.
+	APIKeyFile string
+	APIKeyEnv  string
+	ExtraFile  string
+}
+
+type UpdateModelCommand struct {
+	Name       string
+	Rename     string
+	Identifier string
+	// The API key is only changed when one of these is set
+	APIKeyFile   string
+	APIKeyEnv    string
+	PromptAPIKey bool
+	Extra        string
+	ExtraFile    string
+}
+
+// ApplyModelsCommand makes the custom models match a YAML file
+type ApplyModelsCommand struct {
+	File       string
+	Prune      bool
+	DryRun     bool
+	UpdateKeys bool
+	Yes        bool
 }
 
 type DeleteModelCommand struct {
@@ -76,13 +201,62 @@ type TestModelCommand struct {
 	Name string
 }
 
+// BenchModelsCommand measures latency and throughput of models with
+// streamed calls
+type BenchModelsCommand struct {
+	Names       []string
+	Requests    int
+	Concurrency int
+	Warmup      int
+	Timeout     time.Duration
+	PromptFile  string
+	Format      string
+}
+
+// CompareModelsCommand makes the same call with several models and shows
+// the outputs side by side
+type CompareModelsCommand struct {
+	Models       []string
+	Name         string
+	Instructions string
+	Input        string
+	InputFile    string
+	Judge        string
+	Criteria     string
+	Concurrency  int
+	Timeout      time.Duration
+	Format       string
+}
+
+// ListBuiltinModelsCommand lists the built-in model catalog. Prices are in
+// USD per million tokens.
 type ListBuiltinModelsCommand struct {
-	Filter string
+	Filter            string
+	Providers         []string
+	Locations         []string
+	Capabilities      []string
+	MaxInputPrice     float64
+	MaxOutputPrice    float64
+	MinContext        int
+	ExcludeDeprecated bool
+	Sort              string
+	Reverse           bool
+	Format            string
 }
 
 // Function Commands
 type ListCommand struct {
-	Filter string
+	Filter     string
+	PathGlob   string
+	PathRegex  string
+	Project    string
+	Model      string
+	Metadata   []string
+	HasDataset bool
+	Sort       string
+	Columns    []string
+	Limit      int
+	PageSize   int
 }
 
 type CreateCommand struct {
@@ -105,11 +279,95 @@ type FunctionChatCommand struct {
 
 type ListEvaluationsCommand struct {
 	BaseCommand
-	Limit int
+	Limit    int
+	PageSize int
+}
+
+type ExportEvaluationCommand struct {
+	BaseCommand
+	EvaluationUUID string
+	Format         string
+	OutPath        string
+}
+
+type CompareEvaluationsCommand struct {
+	BaseCommand
+	EvaluationA    string
+	EvaluationB    string
+	Format         string
+	MaxRegressions int
+	Resamples      int
 }
 
 type RunEvaluationCommand struct {
 	BaseCommand
+	Model            string
+	InstructionsFile string
+	FewShotCount     *int
+	Wait             bool
+	Timeout          time.Duration
+	Interval         time.Duration
+	MinAvg           map[string]float64
+	MinMedian        map[string]float64
+}
+
+type FunctionHistoryCommand struct {
+	BaseCommand
+}
+
+type FunctionDiffCommand struct {
+	BaseCommand
+	From int
+	To   int
+}
+
+type FunctionRollbackCommand struct {
+	BaseCommand
+	To int
+}
+
+// Dataset Commands
+type ListDatasetEntriesCommand struct {
+	BaseCommand
+	Limit    int
+	PageSize int
+}
+
+type GetDatasetEntryCommand struct {
+	BaseCommand
+	EntryUUID string
+}
+
+type AddDatasetEntryCommand struct {
+	BaseCommand
+	Input    string
+	Expected string
+	Comment  string
+}
+
+// UpdateDatasetEntryCommand only changes the fields that are non-nil
+type UpdateDatasetEntryCommand struct {
+	BaseCommand
+	EntryUUID string
+	Input     *string
+	Expected  *string
+	Comment   *string
+}
+
+type DeleteDatasetEntryCommand struct {
+	BaseCommand
+	EntryUUID string
+}
+
+type ImportDatasetCommand struct {
+	BaseCommand
+	FilePath string
+	Format   string
+}
+
+type ExportDatasetCommand struct {
+	BaseCommand
+	OutPath string
 }
 
 // Call Commands
@@ -120,6 +378,25 @@ type CallCommand struct {
 	Model        string
 	Stream       bool
 	Tags         map[string]string
+
+	// Index, when set, is queried with the input and the results are added
+	// to the call as cited sources
+	Index    string
+	TopK     int
+	Filters  []string
+	MinScore float64
+	Format   string
+}
+
+// PromptTestCommand runs prompt test files and reports the results
+type PromptTestCommand struct {
+	Paths    []string
+	Format   string
+	OutPath  string
+	Update   bool
+	Model    string
+	Run      string
+	Parallel int
 }
 
 // Config Commands
@@ -5,13 +5,19 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"golang.org/x/term"
 )
 
-// truncateString shortens a string to maxLen characters, adding "..." if truncated
+// truncateString shortens a string to maxLen characters, adding "..." if
+// truncated. Lengths too short for the ellipsis cut the string without it.
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
 	}
+	if maxLen <= 3 {
+		return s[:max(maxLen, 0)]
+	}
 	return s[:maxLen-3] + "..."
 }
 
@@ -31,3 +37,19 @@ func ConfirmDeletion(resourceType, name string, force bool) (bool, error) {
 	response = strings.ToLower(strings.TrimSpace(response))
 	return response == "y" || response == "yes", nil
 }
+
+// promptSecret reads a line from the terminal without echoing it
+func promptSecret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("cannot prompt for input: stdin is not a terminal")
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("error reading input: %w", err)
+	}
+	return strings.TrimSpace(string(secret)), nil
+}
@@ -61,6 +61,7 @@ func main() {
 		builders.BuildVersionCommand(version),
 		builders.BuildCallCommand(executeCommand),
 		builders.BuildUsageCommands(executeCommand),
+		builders.BuildTestCommand(executeCommand),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -4,12 +4,14 @@ go 1.22
 
 require (
 	github.com/google/go-querystring v1.1.0
+	github.com/guptarohit/asciigraph v0.7.3
 	github.com/spf13/cobra v1.8.1
+	golang.org/x/term v0.27.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/guptarohit/asciigraph v0.7.3 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/sys v0.28.0 // indirect
 )
@@ -8,7 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+	"strings"
 )
 
 type CallClient struct {
@@ -21,7 +21,37 @@ func newCallClient(client *Client) *CallClient {
 
 type CallResponse struct {
 	Message string `json:"message"`
-	Stream  chan string
+	// JSONPayload is the structured output of calls made with an output
+	// schema.
+	JSONPayload json.RawMessage `json:"json_payload,omitempty"`
+	SpanID      string          `json:"span_id,omitempty"`
+	Usage       CallUsage       `json:"usage"`
+	Cost        CallCost        `json:"cost"`
+	Stream      chan string     `json:"-"`
+
+	streamErr error
+}
+
+// Err returns the error that cut a streamed response short, or nil if the
+// stream ended normally. It is only meaningful once Stream is closed.
+func (r *CallResponse) Err() error {
+	return r.streamErr
+}
+
+// CallUsage is the token usage of a call. It is only reported for
+// non-streamed calls.
+type CallUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// CallCost is the cost of a call in USD. It is only reported for
+// non-streamed calls.
+type CallCost struct {
+	Generation float64 `json:"generation"`
+	Platform   float64 `json:"platform"`
+	Total      float64 `json:"total"`
 }
 
 func (c *CallClient) Call(ctx context.Context, name string, instructions string, input string, model string, stream bool, tags map[string]string) (*CallResponse, error) {
@@ -49,6 +79,68 @@ func (c *CallClient) Call(ctx context.Context, name string, instructions string,
 		payload["tags"] = tags
 	}
 
+	return c.call(ctx, payload, stream)
+}
+
+// CallFunction calls a stored function with its full configuration: its
+// instructions, input and output schemas and few-shot settings. A non-empty
+// model overrides the function's model. When the function has an input
+// schema the input must be JSON; when it has an output schema the structured
+// result is also returned as compact JSON in Message.
+func (c *CallClient) CallFunction(ctx context.Context, function *FunctionDescription, input string, model string) (*CallResponse, error) {
+	fewShot := 0
+	if function.FewShot {
+		fewShot = function.FewShotCount
+	}
+	if model == "" {
+		model = function.Model
+	}
+
+	payload := map[string]interface{}{
+		"name":         function.Path,
+		"instructions": function.Instructions,
+		"input":        input,
+		"stream":       false,
+		"configuration": map[string]interface{}{
+			"invocation": map[string]interface{}{
+				"few_shot": map[string]interface{}{
+					"count": fewShot,
+				},
+			},
+			"model_parameters": map[string]interface{}{},
+		},
+	}
+	if model != "" {
+		payload["model"] = model
+	}
+	if len(function.InputSchema) > 0 {
+		var structured interface{}
+		if err := json.Unmarshal([]byte(input), &structured); err != nil {
+			return nil, fmt.Errorf("function %s has an input schema, input must be JSON: %w", function.Path, err)
+		}
+		payload["input"] = structured
+		payload["input_schema"] = function.InputSchema
+	}
+	if len(function.OutputSchema) > 0 {
+		payload["output_schema"] = function.OutputSchema
+	}
+
+	response, err := c.call(ctx, payload, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.JSONPayload) > 0 && string(response.JSONPayload) != "null" {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, response.JSONPayload); err == nil {
+			response.Message = buf.String()
+		}
+	}
+	return response, nil
+}
+
+// call sends a /v1/call payload and decodes the response, streaming deltas
+// when stream is set
+func (c *CallClient) call(ctx context.Context, payload map[string]interface{}, stream bool) (*CallResponse, error) {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
@@ -82,6 +174,7 @@ func (c *CallClient) Call(ctx context.Context, name string, instructions string,
 
 	if stream {
 		streamChan := make(chan string)
+		result := &CallResponse{Stream: streamChan}
 		go func() {
 			defer close(streamChan)
 			defer resp.Body.Close()
@@ -91,7 +184,7 @@ func (c *CallClient) Call(ctx context.Context, name string, instructions string,
 				line, err := reader.ReadBytes('\n')
 				if err != nil {
 					if err != io.EOF {
-						fmt.Fprintf(os.Stderr, "Error reading stream: %v\n", err)
+						result.streamErr = fmt.Errorf("error reading stream: %w", err)
 					}
 					return
 				}
@@ -117,7 +210,7 @@ func (c *CallClient) Call(ctx context.Context, name string, instructions string,
 				}
 			}
 		}()
-		return &CallResponse{Stream: streamChan}, nil
+		return result, nil
 	}
 
 	// For non-streaming responses
@@ -127,12 +220,24 @@ func (c *CallClient) Call(ctx context.Context, name string, instructions string,
 		return nil, fmt.Errorf("error reading response: %w", err)
 	}
 
-	var result struct {
-		Message string `json:"message"`
-	}
+	var result CallResponse
 	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("error parsing response: %w", err)
 	}
 
-	return &CallResponse{Message: result.Message}, nil
+	return &result, nil
+}
+
+// StripCodeFence removes a surrounding Markdown code fence, which models
+// often add around JSON
+func StripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
 }
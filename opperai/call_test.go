@@ -76,3 +76,124 @@ func TestCallClient_Call(t *testing.T) {
 		})
 	}
 }
+
+func TestCallClient_CallUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message": "hi", "span_id": "s1", "usage": {"input_tokens": 10, "output_tokens": 2, "total_tokens": 12}, "cost": {"generation": 0.001, "platform": 0.0005, "total": 0.0015}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	result, err := client.Call.Call(context.Background(), "test-name", "test-instructions", "test-input", "", false, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.SpanID != "s1" || result.Usage.TotalTokens != 12 || result.Cost.Total != 0.0015 {
+		t.Errorf("unexpected response %+v", result)
+	}
+}
+
+func TestCallClient_CallStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("data: {\"delta\": \"Hel\"}\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	resp, err := client.Call.Call(context.Background(), "test", "", "hi", "", true, nil)
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	var got string
+	for delta := range resp.Stream {
+		got += delta
+	}
+	if got != "Hel" {
+		t.Errorf("streamed %q, want %q", got, "Hel")
+	}
+	if resp.Err() == nil {
+		t.Error("expected an error for a truncated stream")
+	}
+}
+
+func TestCallClient_CallFunction(t *testing.T) {
+	function := &FunctionDescription{
+		Path:         "extract/person",
+		Instructions: "extract the person",
+		Model:        "openai/gpt-4o",
+		FewShot:      true,
+		FewShotCount: 3,
+		InputSchema:  map[string]interface{}{"type": "object"},
+		OutputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}}},
+	}
+
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"message": "", "json_payload": {"name": "Ada"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	resp, err := client.Call.CallFunction(context.Background(), function, `{"text": "Ada wrote it"}`, "")
+	if err != nil {
+		t.Fatalf("CallFunction() error = %v", err)
+	}
+	if resp.Message != `{"name":"Ada"}` {
+		t.Errorf("Message = %q, want the compact JSON payload", resp.Message)
+	}
+
+	if payload["name"] != "extract/person" || payload["instructions"] != "extract the person" || payload["model"] != "openai/gpt-4o" {
+		t.Errorf("unexpected payload %v", payload)
+	}
+	if input, ok := payload["input"].(map[string]interface{}); !ok || input["text"] != "Ada wrote it" {
+		t.Errorf("input = %v, want a JSON object", payload["input"])
+	}
+	if payload["input_schema"] == nil || payload["output_schema"] == nil {
+		t.Errorf("schemas missing from payload %v", payload)
+	}
+	config := payload["configuration"].(map[string]interface{})
+	fewShot := config["invocation"].(map[string]interface{})["few_shot"].(map[string]interface{})
+	if fewShot["count"] != float64(3) {
+		t.Errorf("few_shot count = %v, want 3", fewShot["count"])
+	}
+
+	if _, err := client.Call.CallFunction(context.Background(), function, "not json", "other/model"); err == nil {
+		t.Error("expected an error for a non-JSON input with an input schema")
+	}
+
+	plain := &FunctionDescription{Path: "greet", Instructions: "say hi", Model: "openai/gpt-4o", FewShotCount: 3}
+	if _, err := client.Call.CallFunction(context.Background(), plain, "bob", "other/model"); err != nil {
+		t.Fatalf("CallFunction() error = %v", err)
+	}
+	if payload["input"] != "bob" || payload["model"] != "other/model" || payload["input_schema"] != nil || payload["output_schema"] != nil {
+		t.Errorf("unexpected payload %v", payload)
+	}
+	fewShot = payload["configuration"].(map[string]interface{})["invocation"].(map[string]interface{})["few_shot"].(map[string]interface{})
+	if fewShot["count"] != float64(0) {
+		t.Errorf("few_shot count = %v, want 0 when few-shot is off", fewShot["count"])
+	}
+}
+
+func TestStripCodeFence(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "no fence", in: ` {"a": 1} `, want: `{"a": 1}`},
+		{name: "json fence", in: "```json\n{\"a\": 1}\n```", want: `{"a": 1}`},
+		{name: "bare fence", in: "```\n[1, 2]\n```\n", want: `[1, 2]`},
+		{name: "unterminated fence", in: "```json\n{\"a\": 1}", want: `{"a": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripCodeFence(tt.in); got != tt.want {
+				t.Errorf("StripCodeFence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+// Package chunking splits local files into overlapping chunks suitable for
+// adding to an index as documents.
+//
+// Chunk boundaries follow the structure of the input: Markdown is split by
+// heading, source code by top-level declarations and blank-line blocks, and
+// other text by paragraph. Structural units are packed into chunks of up to
+// Options.Size characters, and consecutive chunks share up to
+// Options.Overlap characters of trailing lines.
+package chunking
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+const (
+	DefaultSize    = 1500
+	DefaultOverlap = 200
+	// MinSize is the smallest chunk size accepted by New
+	MinSize = 50
+	// minPackSize keeps room for content next to the separators a chunk adds
+	minPackSize = 10
+)
+
+// Options controls chunk size and overlap, both in characters. A zero Size
+// uses DefaultSize.
+type Options struct {
+	Size    int
+	Overlap int
+}
+
+// Validate reports options that cannot produce useful chunks.
+func (o Options) Validate() error {
+	if o.Size != 0 && o.Size < MinSize {
+		return fmt.Errorf("chunk size must be at least %d characters, got %d", MinSize, o.Size)
+	}
+	return nil
+}
+
+// withDefaults fills in defaults and raises tiny sizes, so chunkers built
+// without New cannot fail to split long lines
+func (o Options) withDefaults() Options {
+	if o.Size <= 0 {
+		o.Size = DefaultSize
+	}
+	if o.Size < minPackSize {
+		o.Size = minPackSize
+	}
+	if o.Overlap < 0 {
+		o.Overlap = 0
+	}
+	if o.Overlap >= o.Size {
+		o.Overlap = o.Size / 2
+	}
+	return o
+}
+
+// Chunk is a piece of a file. Line numbers are 1-based and inclusive.
+type Chunk struct {
+	Content   string
+	Index     int
+	StartLine int
+	EndLine   int
+	// Heading is the Markdown heading path of the chunk, outermost first.
+	Heading []string
+}
+
+// Chunker splits text into chunks.
+type Chunker interface {
+	Chunk(text string) []Chunk
+}
+
+// Strategies accepted by New.
+const (
+	StrategyAuto     = "auto"
+	StrategyText     = "text"
+	StrategyMarkdown = "markdown"
+	StrategyCode     = "code"
+)
+
+// New returns the chunker for a strategy. The auto strategy picks one based
+// on the file extension of path. Sizes below MinSize are rejected.
+func New(strategy, path string, opts Options) (Chunker, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(strategy) {
+	case "", StrategyAuto:
+		return ForFile(path, opts), nil
+	case StrategyText:
+		return Text(opts), nil
+	case StrategyMarkdown, "md":
+		return Markdown(opts), nil
+	case StrategyCode:
+		return Code(opts), nil
+	default:
+		return nil, fmt.Errorf("unknown chunking strategy: %s (must be auto, text, markdown or code)", strategy)
+	}
+}
+
+var codeExtensions = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true,
+	".java": true, ".kt": true, ".scala": true, ".rb": true, ".rs": true, ".c": true,
+	".h": true, ".cc": true, ".cpp": true, ".hpp": true, ".cs": true, ".php": true,
+	".swift": true, ".sh": true, ".sql": true, ".lua": true, ".ex": true, ".exs": true,
+}
+
+// ForFile picks a chunker from the file extension.
+func ForFile(path string, opts Options) Chunker {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch {
+	case ext == ".md" || ext == ".markdown" || ext == ".mdx":
+		return Markdown(opts)
+	case codeExtensions[ext]:
+		return Code(opts)
+	default:
+		return Text(opts)
+	}
+}
+
+// Documents converts chunks of a source file into index documents. Keys are
+// "<source>#<index>", so adding the same file again replaces its chunks.
+// Each document's metadata holds the source, chunk index and count, line
+// range and heading path, on top of any extra metadata.
+func Documents(source string, chunks []Chunk, extra map[string]interface{}) []opperai.Document {
+	docs := make([]opperai.Document, len(chunks))
+	for i, chunk := range chunks {
+		metadata := make(map[string]interface{}, len(extra)+6)
+		for k, v := range extra {
+			metadata[k] = v
+		}
+		metadata["source"] = source
+		metadata["chunk_index"] = chunk.Index
+		metadata["chunk_count"] = len(chunks)
+		metadata["start_line"] = chunk.StartLine
+		metadata["end_line"] = chunk.EndLine
+		if len(chunk.Heading) > 0 {
+			metadata["heading"] = strings.Join(chunk.Heading, " > ")
+		}
+
+		docs[i] = opperai.Document{
+			Key:      fmt.Sprintf("%s#%d", source, chunk.Index),
+			Content:  chunk.Content,
+			Metadata: metadata,
+		}
+	}
+	return docs
+}
+
+// line is a line of input with its 1-based line number. A gap line starts a
+// new block and is preceded by a blank line in chunk content.
+type line struct {
+	text string
+	num  int
+	gap  bool
+}
+
+// block is a run of lines that should stay together when possible
+type block []line
+
+func splitLines(text string) []line {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	raw := strings.Split(text, "\n")
+	lines := make([]line, len(raw))
+	for i, s := range raw {
+		lines[i] = line{text: s, num: i + 1}
+	}
+	return lines
+}
+
+func isBlank(l line) bool {
+	return strings.TrimSpace(l.text) == ""
+}
+
+// paragraphs splits lines into blocks separated by blank lines
+func paragraphs(lines []line) []block {
+	var blocks []block
+	var cur block
+	for _, l := range lines {
+		if isBlank(l) {
+			if len(cur) > 0 {
+				blocks = append(blocks, cur)
+				cur = nil
+			}
+			continue
+		}
+		cur = append(cur, l)
+	}
+	if len(cur) > 0 {
+		blocks = append(blocks, cur)
+	}
+	return blocks
+}
+
+// packer accumulates blocks into chunks of at most size characters
+type packer struct {
+	opts    Options
+	heading []string
+	chunks  []Chunk
+
+	cur   []line
+	fresh int // lines added since the last chunk was emitted
+}
+
+func newPacker(opts Options) *packer {
+	return &packer{opts: opts}
+}
+
+func lineSize(l line) int {
+	if l.gap {
+		return len(l.text) + 2
+	}
+	return len(l.text) + 1
+}
+
+func linesSize(lines []line) int {
+	n := 0
+	for _, l := range lines {
+		n += lineSize(l)
+	}
+	return n
+}
+
+// add appends a block, starting a new chunk first if the block does not fit
+// in the current one. Blocks larger than a chunk are split by line, and
+// lines longer than a chunk are split by character.
+func (p *packer) add(b block) {
+	if p.fresh > 0 && linesSize(p.cur)+linesSize(b) > p.opts.Size {
+		p.emit()
+	}
+
+	for i, l := range b {
+		for j, piece := range splitLongLine(l, p.opts.Size-2) {
+			piece.gap = i == 0 && j == 0
+			if p.fresh > 0 && linesSize(p.cur)+lineSize(piece) > p.opts.Size {
+				p.emit()
+			}
+			// Drop overlap that would leave no room for new content
+			for len(p.cur) > 0 && p.fresh == 0 && linesSize(p.cur)+lineSize(piece) > p.opts.Size {
+				p.cur = p.cur[1:]
+			}
+			p.cur = append(p.cur, piece)
+			p.fresh++
+		}
+	}
+}
+
+// flush emits any pending content and drops the overlap, so the next block
+// starts a fresh chunk. It is used at section boundaries.
+func (p *packer) flush() {
+	if p.fresh > 0 {
+		p.emit()
+	}
+	p.cur = nil
+}
+
+func (p *packer) emit() {
+	var sb strings.Builder
+	for i, l := range p.cur {
+		if i > 0 {
+			sb.WriteByte('\n')
+			if l.gap {
+				sb.WriteByte('\n')
+			}
+		}
+		sb.WriteString(l.text)
+	}
+	p.chunks = append(p.chunks, Chunk{
+		Content:   sb.String(),
+		Index:     len(p.chunks),
+		StartLine: p.cur[0].num,
+		EndLine:   p.cur[len(p.cur)-1].num,
+		Heading:   append([]string(nil), p.heading...),
+	})
+
+	// Keep trailing lines as overlap for the next chunk
+	var tail []line
+	size := 0
+	for i := len(p.cur) - 1; i >= 0; i-- {
+		size += lineSize(p.cur[i])
+		if size > p.opts.Overlap {
+			break
+		}
+		tail = p.cur[i:]
+	}
+	p.cur = append([]line(nil), tail...)
+	p.fresh = 0
+}
+
+func (p *packer) result() []Chunk {
+	p.flush()
+	return p.chunks
+}
+
+func splitLongLine(l line, max int) []line {
+	if len(l.text) <= max {
+		return []line{l}
+	}
+	var pieces []line
+	text := l.text
+	for len(text) > max {
+		cut := max
+		// Avoid splitting a multi-byte character
+		for cut > 0 && !isRuneStart(text[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = max
+		}
+		pieces = append(pieces, line{text: text[:cut], num: l.num})
+		text = text[cut:]
+	}
+	return append(pieces, line{text: text, num: l.num})
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,242 @@
+package chunking
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTextChunking(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		opts   Options
+		chunks []string
+	}{
+		{
+			name:   "empty",
+			text:   "\n\n",
+			opts:   Options{Size: 100},
+			chunks: nil,
+		},
+		{
+			name:   "fits in one chunk",
+			text:   "one\ntwo\n\nthree\n",
+			opts:   Options{Size: 100},
+			chunks: []string{"one\ntwo\n\nthree"},
+		},
+		{
+			name:   "paragraphs kept together",
+			text:   "aaaa\nbbbb\n\ncccc\ndddd\n",
+			opts:   Options{Size: 15},
+			chunks: []string{"aaaa\nbbbb", "cccc\ndddd"},
+		},
+		{
+			name:   "overlap repeats trailing lines",
+			text:   "aaaa\nbbbb\ncccc\ndddd",
+			opts:   Options{Size: 16, Overlap: 5},
+			chunks: []string{"aaaa\nbbbb\ncccc", "cccc\ndddd"},
+		},
+		{
+			name:   "long line split by character",
+			text:   strings.Repeat("x", 25),
+			opts:   Options{Size: 12},
+			chunks: []string{strings.Repeat("x", 10), strings.Repeat("x", 10), "xxxxx"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := Text(tt.opts).Chunk(tt.text)
+			var got []string
+			for i, c := range chunks {
+				if c.Index != i {
+					t.Errorf("chunk %d has index %d", i, c.Index)
+				}
+				if len(c.Content)+1 > tt.opts.Size {
+					t.Errorf("chunk %d is %d characters, larger than %d", i, len(c.Content), tt.opts.Size)
+				}
+				got = append(got, c.Content)
+			}
+			if !reflect.DeepEqual(got, tt.chunks) {
+				t.Errorf("got chunks %q, want %q", got, tt.chunks)
+			}
+		})
+	}
+}
+
+func TestMarkdownChunking(t *testing.T) {
+	text := `Intro text.
+
+# Guide
+
+Welcome.
+
+## Install
+
+Run this:
+
+` + "```sh\nmake install\n\nmake test\n```" + `
+
+### Linux
+
+#### Debian
+
+Use apt.
+
+## Usage
+
+Call it.
+`
+	chunks := Markdown(Options{Size: 200}).Chunk(text)
+
+	want := []struct {
+		heading   []string
+		content   string
+		startLine int
+		endLine   int
+	}{
+		{nil, "Intro text.", 1, 1},
+		{[]string{"Guide"}, "# Guide\n\nWelcome.", 3, 5},
+		{[]string{"Guide", "Install"}, "## Install\n\nRun this:\n\n```sh\nmake install\n\nmake test\n```", 7, 15},
+		{[]string{"Guide", "Install", "Linux", "Debian"}, "### Linux\n\n#### Debian\n\nUse apt.", 17, 21},
+		{[]string{"Guide", "Usage"}, "## Usage\n\nCall it.", 23, 25},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks, want %d: %+v", len(chunks), len(want), chunks)
+	}
+	for i, w := range want {
+		c := chunks[i]
+		if !reflect.DeepEqual(c.Heading, w.heading) {
+			t.Errorf("chunk %d: heading %q, want %q", i, c.Heading, w.heading)
+		}
+		if c.Content != w.content {
+			t.Errorf("chunk %d: content %q, want %q", i, c.Content, w.content)
+		}
+		if c.StartLine != w.startLine || c.EndLine != w.endLine {
+			t.Errorf("chunk %d: lines %d-%d, want %d-%d", i, c.StartLine, c.EndLine, w.startLine, w.endLine)
+		}
+	}
+}
+
+func TestCodeChunking(t *testing.T) {
+	text := `package main
+
+import "fmt"
+
+// greet prints a greeting
+func greet(name string) {
+	msg := "hi " + name
+
+	fmt.Println(msg)
+}
+func main() {
+	greet("bob")
+}
+`
+	chunks := Code(Options{Size: 120, Overlap: 0}).Chunk(text)
+
+	var got []string
+	for _, c := range chunks {
+		got = append(got, c.Content)
+	}
+	want := []string{
+		"package main\n\nimport \"fmt\"",
+		"// greet prints a greeting\nfunc greet(name string) {\n\tmsg := \"hi \" + name\n\n\tfmt.Println(msg)\n}",
+		"func main() {\n\tgreet(\"bob\")\n}",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got chunks %q, want %q", got, want)
+	}
+	if chunks[1].StartLine != 5 || chunks[1].EndLine != 10 {
+		t.Errorf("function chunk spans lines %d-%d, want 5-10", chunks[1].StartLine, chunks[1].EndLine)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		strategy string
+		path     string
+		want     Chunker
+		wantErr  bool
+	}{
+		{"auto", "docs/guide.md", markdownChunker{}, false},
+		{"", "main.go", codeChunker{}, false},
+		{"auto", "notes.txt", textChunker{}, false},
+		{"code", "notes.txt", codeChunker{}, false},
+		{"markdown", "main.go", markdownChunker{}, false},
+		{"semantic", "a.txt", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy+" "+tt.path, func(t *testing.T) {
+			got, err := New(tt.strategy, tt.path, Options{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if reflect.TypeOf(got) != reflect.TypeOf(tt.want) {
+				t.Errorf("got %T, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmallSizes(t *testing.T) {
+	for _, size := range []int{1, 2, 3, MinSize - 1} {
+		if _, err := New("text", "a.txt", Options{Size: size}); err == nil {
+			t.Errorf("New with size %d: expected error", size)
+		}
+	}
+	if _, err := New("text", "a.txt", Options{Size: MinSize}); err != nil {
+		t.Errorf("New with size %d: unexpected error %v", MinSize, err)
+	}
+
+	// Chunkers built directly must still split long lines and terminate
+	text := strings.Repeat("x", 40) + "\n" + strings.Repeat("y", 25)
+	for _, size := range []int{1, 2} {
+		for _, chunker := range []Chunker{Text(Options{Size: size}), Markdown(Options{Size: size}), Code(Options{Size: size})} {
+			chunks := chunker.Chunk(text)
+			var joined strings.Builder
+			for _, c := range chunks {
+				if len(c.Content) > minPackSize {
+					t.Errorf("%T size %d: chunk %q longer than %d", chunker, size, c.Content, minPackSize)
+				}
+				joined.WriteString(strings.TrimSpace(c.Content))
+			}
+			if got := strings.Count(joined.String(), "x") + strings.Count(joined.String(), "y"); got != 65 {
+				t.Errorf("%T size %d: chunks hold %d characters, want 65", chunker, size, got)
+			}
+		}
+	}
+}
+
+func TestDocuments(t *testing.T) {
+	chunks := []Chunk{
+		{Content: "# A\ntext", Index: 0, StartLine: 1, EndLine: 2, Heading: []string{"A"}},
+		{Content: "more", Index: 1, StartLine: 4, EndLine: 4},
+	}
+	docs := Documents("docs/a.md", chunks, map[string]interface{}{"project": "cli", "source": "ignored"})
+
+	if len(docs) != 2 {
+		t.Fatalf("got %d documents, want 2", len(docs))
+	}
+	if docs[0].Key != "docs/a.md#0" || docs[1].Key != "docs/a.md#1" {
+		t.Errorf("got keys %q and %q", docs[0].Key, docs[1].Key)
+	}
+
+	want := map[string]interface{}{
+		"project":     "cli",
+		"source":      "docs/a.md",
+		"chunk_index": 0,
+		"chunk_count": 2,
+		"start_line":  1,
+		"end_line":    2,
+		"heading":     "A",
+	}
+	if !reflect.DeepEqual(docs[0].Metadata, want) {
+		t.Errorf("got metadata %v, want %v", docs[0].Metadata, want)
+	}
+	if _, ok := docs[1].Metadata["heading"]; ok {
+		t.Errorf("chunk without heading has heading metadata: %v", docs[1].Metadata)
+	}
+}
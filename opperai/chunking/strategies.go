@@ -0,0 +1,168 @@
+package chunking
+
+import (
+	"regexp"
+	"strings"
+)
+
+type textChunker struct{ opts Options }
+
+// Text returns a chunker that keeps paragraphs together.
+func Text(opts Options) Chunker {
+	return textChunker{opts.withDefaults()}
+}
+
+func (c textChunker) Chunk(text string) []Chunk {
+	p := newPacker(c.opts)
+	for _, b := range paragraphs(splitLines(text)) {
+		p.add(b)
+	}
+	return p.result()
+}
+
+type markdownChunker struct{ opts Options }
+
+// Markdown returns a chunker that starts a new chunk at every heading and
+// records the heading path of each chunk. Fenced code blocks are kept whole
+// when they fit in a chunk.
+func Markdown(opts Options) Chunker {
+	return markdownChunker{opts.withDefaults()}
+}
+
+var headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+
+func (c markdownChunker) Chunk(text string) []Chunk {
+	p := newPacker(c.opts)
+	var stack []string
+	var cur block
+	fence := ""
+	// A heading directly followed by another heading stays in the same chunk
+	hasBody := false
+
+	endBlock := func() {
+		if len(cur) > 0 {
+			p.add(cur)
+			cur = nil
+			hasBody = true
+		}
+	}
+
+	for _, l := range splitLines(text) {
+		trimmed := strings.TrimSpace(l.text)
+
+		if fence != "" {
+			cur = append(cur, l)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				endBlock()
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			endBlock()
+			fence = trimmed[:3]
+			cur = append(cur, l)
+			continue
+		}
+
+		if m := headingRe.FindStringSubmatch(l.text); m != nil {
+			endBlock()
+			if hasBody {
+				p.flush()
+			}
+			level := len(m[1])
+			if len(stack) >= level {
+				stack = stack[:level-1]
+			}
+			for len(stack) < level-1 {
+				stack = append(stack, "")
+			}
+			stack = append(stack, m[2])
+			p.heading = compact(stack)
+			p.add(block{l})
+			hasBody = false
+			continue
+		}
+
+		if trimmed == "" {
+			endBlock()
+			continue
+		}
+		cur = append(cur, l)
+	}
+	endBlock()
+
+	return p.result()
+}
+
+// compact drops placeholders for skipped heading levels
+func compact(headings []string) []string {
+	var out []string
+	for _, h := range headings {
+		if h != "" {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+type codeChunker struct{ opts Options }
+
+// Code returns a chunker for source code. Blocks start at top-level
+// declarations and at unindented lines after a blank line, so functions
+// stay together with their leading comments where possible.
+func Code(opts Options) Chunker {
+	return codeChunker{opts.withDefaults()}
+}
+
+var declarationRe = regexp.MustCompile(`^(func|def|async def|class|type|struct|enum|interface|impl|trait|fn|pub |export |function|public |private |protected |static |package |module |const |var |let |@)`)
+
+func (c codeChunker) Chunk(text string) []Chunk {
+	p := newPacker(c.opts)
+	var cur block
+	prevBlank := false
+
+	for _, l := range splitLines(text) {
+		blank := isBlank(l)
+		topLevel := !blank && l.text[0] != ' ' && l.text[0] != '\t'
+
+		startsBlock := topLevel && (prevBlank || (declarationRe.MatchString(l.text) && !continuesDeclaration(cur)))
+		if startsBlock && len(cur) > 0 {
+			p.add(trimBlankLines(cur))
+			cur = nil
+		}
+		if !(blank && len(cur) == 0) {
+			cur = append(cur, l)
+		}
+		prevBlank = blank
+	}
+	if len(cur) > 0 {
+		p.add(trimBlankLines(cur))
+	}
+
+	return p.result()
+}
+
+// continuesDeclaration reports whether the block so far is only comments or
+// decorators, which belong to the declaration that follows them
+func continuesDeclaration(b block) bool {
+	if len(b) == 0 {
+		return false
+	}
+	for _, l := range b {
+		t := strings.TrimSpace(l.text)
+		if !(strings.HasPrefix(t, "//") || strings.HasPrefix(t, "#") || strings.HasPrefix(t, "/*") ||
+			strings.HasPrefix(t, "*") || strings.HasPrefix(t, "@")) {
+			return false
+		}
+	}
+	return true
+}
+
+func trimBlankLines(b block) block {
+	for len(b) > 0 && isBlank(b[len(b)-1]) {
+		b = b[:len(b)-1]
+	}
+	return b
+}
@@ -0,0 +1,325 @@
+package opperai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type DatasetsClient struct {
+	client *Client
+}
+
+func newDatasetsClient(client *Client) *DatasetsClient {
+	return &DatasetsClient{client: client}
+}
+
+// ListEntries returns a single page of entries in a dataset.
+func (c *DatasetsClient) ListEntries(ctx context.Context, datasetUUID string, offset, limit int) (*DatasetEntriesResponse, error) {
+	endpoint := fmt.Sprintf("/api/v1/datasets/%s/entries?offset=%d&limit=%d", datasetUUID, offset, limit)
+	resp, err := c.client.DoRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("dataset not found: %s", datasetUUID)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list dataset entries with status %s", resp.Status)
+	}
+
+	var response DatasetEntriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// IterEntries returns an iterator over all entries in a dataset.
+func (c *DatasetsClient) IterEntries(ctx context.Context, datasetUUID string, opts *ListOptions) *Iterator[DatasetEntry] {
+	offset := 0
+	return newIterator(ctx, opts, func(ctx context.Context, pageSize int) (*page[DatasetEntry], error) {
+		response, err := c.ListEntries(ctx, datasetUUID, offset, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		offset += len(response.Data)
+		return &page[DatasetEntry]{
+			items: response.Data,
+			more:  offset < response.Meta.TotalCount,
+			total: response.Meta.TotalCount,
+		}, nil
+	})
+}
+
+func (c *DatasetsClient) GetEntry(ctx context.Context, datasetUUID, entryUUID string) (*DatasetEntry, error) {
+	endpoint := fmt.Sprintf("/api/v1/datasets/%s/entries/%s", datasetUUID, entryUUID)
+	resp, err := c.client.DoRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("dataset entry not found: %s", entryUUID)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get dataset entry with status %s", resp.Status)
+	}
+
+	var entry DatasetEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func (c *DatasetsClient) AddEntry(ctx context.Context, datasetUUID string, entry DatasetEntry) (*DatasetEntry, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/datasets/%s/entries", datasetUUID)
+	resp, err := c.client.DoRequest(ctx, "POST", endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("dataset not found: %s", datasetUUID)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to add dataset entry with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var created DatasetEntry
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &created, nil
+}
+
+func (c *DatasetsClient) UpdateEntry(ctx context.Context, datasetUUID, entryUUID string, entry DatasetEntry) (*DatasetEntry, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/datasets/%s/entries/%s", datasetUUID, entryUUID)
+	resp, err := c.client.DoRequest(ctx, "PATCH", endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("dataset entry not found: %s", entryUUID)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to update dataset entry with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var updated DatasetEntry
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &updated, nil
+}
+
+func (c *DatasetsClient) DeleteEntry(ctx context.Context, datasetUUID, entryUUID string) error {
+	endpoint := fmt.Sprintf("/api/v1/datasets/%s/entries/%s", datasetUUID, entryUUID)
+	resp, err := c.client.DoRequest(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("dataset entry not found: %s", entryUUID)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete dataset entry with status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// ReadDatasetEntries parses dataset entries from JSONL or CSV.
+//
+// JSONL lines are objects with "input", "expected" (or "output") and
+// "comment" fields; non-string values are stored as compact JSON. CSV input
+// must start with a header row naming the same columns.
+func ReadDatasetEntries(r io.Reader, format string) ([]DatasetEntry, error) {
+	switch strings.ToLower(format) {
+	case "jsonl", "ndjson":
+		return readDatasetEntriesJSONL(r)
+	case "csv":
+		return readDatasetEntriesCSV(r)
+	default:
+		return nil, fmt.Errorf("unsupported dataset format: %s (must be jsonl or csv)", format)
+	}
+}
+
+// WriteDatasetEntriesJSONL writes entries as one JSON object per line.
+func WriteDatasetEntriesJSONL(w io.Writer, entries []DatasetEntry) error {
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readDatasetEntriesJSONL(r io.Reader) ([]DatasetEntry, error) {
+	var entries []DatasetEntry
+	err := ScanJSONL(r, func(lineNo int, raw map[string]json.RawMessage) error {
+		entry := DatasetEntry{
+			Input:   RawToString(raw["input"]),
+			Output:  RawToString(raw["expected"]),
+			Comment: RawToString(raw["comment"]),
+		}
+		if entry.Output == "" {
+			entry.Output = RawToString(raw["output"])
+		}
+		if entry.Input == "" {
+			return fmt.Errorf("missing input")
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ScanJSONL decodes each line into a T and passes it to fn, skipping blank
+// lines. Errors are prefixed with the line number.
+func ScanJSONL[T any](r io.Reader, fn func(lineNo int, v T) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var v T
+		if err := json.Unmarshal(line, &v); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				return fmt.Errorf("line %d: invalid JSON: %w", lineNo, err)
+			}
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if err := fn(lineNo, v); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func readDatasetEntriesCSV(r io.Reader) ([]DatasetEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	inputCol, ok := columns["input"]
+	if !ok {
+		return nil, fmt.Errorf("CSV header must contain an input column")
+	}
+	expectedCol, ok := columns["expected"]
+	if !ok {
+		expectedCol, ok = columns["output"]
+		if !ok {
+			expectedCol = -1
+		}
+	}
+	commentCol, ok := columns["comment"]
+	if !ok {
+		commentCol = -1
+	}
+
+	field := func(record []string, col int) string {
+		if col < 0 || col >= len(record) {
+			return ""
+		}
+		return record[col]
+	}
+
+	var entries []DatasetEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV: %w", err)
+		}
+
+		entry := DatasetEntry{
+			Input:   field(record, inputCol),
+			Output:  field(record, expectedCol),
+			Comment: field(record, commentCol),
+		}
+		if entry.Input == "" {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: missing input", line)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// RawToString returns JSON strings unquoted and any other value as compact JSON
+func RawToString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
@@ -0,0 +1,283 @@
+package opperai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const datasetsBasePath = "/api/v1/datasets"
+
+func TestListDatasetEntries(t *testing.T) {
+	tests := []struct {
+		name        string
+		datasetUUID string
+		response    []DatasetEntry
+		statusCode  int
+		wantErr     bool
+	}{
+		{
+			name:        "successful list",
+			datasetUUID: "ds-uuid",
+			response: []DatasetEntry{
+				{UUID: "e1", Input: "2+2", Output: "4"},
+				{UUID: "e2", Input: "3+3", Output: "6"},
+			},
+			statusCode: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:        "dataset not found",
+			datasetUUID: "nonexistent",
+			statusCode:  http.StatusNotFound,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("expected GET request, got %s", r.Method)
+				}
+				expectedPath := fmt.Sprintf("%s/%s/entries", datasetsBasePath, tt.datasetUUID)
+				if r.URL.Path != expectedPath {
+					t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+				}
+
+				w.WriteHeader(tt.statusCode)
+				if tt.statusCode == http.StatusOK {
+					response := DatasetEntriesResponse{Data: tt.response}
+					response.Meta.TotalCount = len(tt.response)
+					json.NewEncoder(w).Encode(response)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL)
+			entries, err := client.Datasets.IterEntries(context.Background(), tt.datasetUUID, nil).All()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IterEntries() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err == nil {
+				if len(entries) != len(tt.response) {
+					t.Fatalf("expected %d entries, got %d", len(tt.response), len(entries))
+				}
+				for i, entry := range entries {
+					if entry.UUID != tt.response[i].UUID {
+						t.Errorf("expected entry %s, got %s", tt.response[i].UUID, entry.UUID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAddDatasetEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		entry      DatasetEntry
+		statusCode int
+		wantErr    bool
+	}{
+		{
+			name:       "successful add",
+			entry:      DatasetEntry{Input: "2+2", Output: "4", Comment: "arithmetic"},
+			statusCode: http.StatusCreated,
+			wantErr:    false,
+		},
+		{
+			name:       "server error",
+			entry:      DatasetEntry{Input: "2+2"},
+			statusCode: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("expected POST request, got %s", r.Method)
+				}
+				expectedPath := fmt.Sprintf("%s/ds-uuid/entries", datasetsBasePath)
+				if r.URL.Path != expectedPath {
+					t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+				}
+
+				var entry DatasetEntry
+				if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+					t.Errorf("failed to decode request body: %v", err)
+				}
+				if entry != tt.entry {
+					t.Errorf("expected entry %+v, got %+v", tt.entry, entry)
+				}
+
+				w.WriteHeader(tt.statusCode)
+				if tt.statusCode == http.StatusCreated {
+					entry.UUID = "new-uuid"
+					json.NewEncoder(w).Encode(entry)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL)
+			created, err := client.Datasets.AddEntry(context.Background(), "ds-uuid", tt.entry)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddEntry() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err == nil && created.UUID != "new-uuid" {
+				t.Errorf("expected uuid new-uuid, got %s", created.UUID)
+			}
+		})
+	}
+}
+
+func TestDeleteDatasetEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{
+			name:       "successful delete",
+			statusCode: http.StatusNoContent,
+			wantErr:    false,
+		},
+		{
+			name:       "entry not found",
+			statusCode: http.StatusNotFound,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("expected DELETE request, got %s", r.Method)
+				}
+				expectedPath := fmt.Sprintf("%s/ds-uuid/entries/e1", datasetsBasePath)
+				if r.URL.Path != expectedPath {
+					t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+				}
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL)
+			err := client.Datasets.DeleteEntry(context.Background(), "ds-uuid", "e1")
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadDatasetEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		data    string
+		want    []DatasetEntry
+		wantErr bool
+	}{
+		{
+			name:   "jsonl",
+			format: "jsonl",
+			data: `{"input": "2+2", "expected": "4", "comment": "easy"}
+
+{"input": {"a": 1}, "output": "1"}
+`,
+			want: []DatasetEntry{
+				{Input: "2+2", Output: "4", Comment: "easy"},
+				{Input: `{"a":1}`, Output: "1"},
+			},
+		},
+		{
+			name:    "jsonl missing input",
+			format:  "jsonl",
+			data:    `{"expected": "4"}`,
+			wantErr: true,
+		},
+		{
+			name:   "csv",
+			format: "csv",
+			data:   "Input,Expected,Comment\n\"hello, world\",greeting,\n2+2,4,math\n",
+			want: []DatasetEntry{
+				{Input: "hello, world", Output: "greeting"},
+				{Input: "2+2", Output: "4", Comment: "math"},
+			},
+		},
+		{
+			name:    "csv without input column",
+			format:  "csv",
+			data:    "question,answer\nq,a\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			format:  "xml",
+			data:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ReadDatasetEntries(strings.NewReader(tt.data), tt.format)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadDatasetEntries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil {
+				if len(entries) != len(tt.want) {
+					t.Fatalf("expected %d entries, got %d", len(tt.want), len(entries))
+				}
+				for i, entry := range entries {
+					if entry != tt.want[i] {
+						t.Errorf("expected entry %+v, got %+v", tt.want[i], entry)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWriteDatasetEntriesJSONLRoundTrip(t *testing.T) {
+	entries := []DatasetEntry{
+		{Input: "2+2", Output: "4", Comment: "math"},
+		{Input: "line\nbreak", Output: "ok"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDatasetEntriesJSONL(&buf, entries); err != nil {
+		t.Fatalf("WriteDatasetEntriesJSONL() unexpected error = %v", err)
+	}
+
+	got, err := ReadDatasetEntries(&buf, "jsonl")
+	if err != nil {
+		t.Fatalf("ReadDatasetEntries() unexpected error = %v", err)
+	}
+	if len(got) != len(entries) {
+		t.Fatalf("expected %d entries, got %d", len(entries), len(got))
+	}
+	for i := range entries {
+		if got[i] != entries[i] {
+			t.Errorf("expected entry %+v, got %+v", entries[i], got[i])
+		}
+	}
+}
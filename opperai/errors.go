@@ -1,14 +1,34 @@
 package opperai
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrRateLimit       = errors.New("rate limit error: please retry in a few seconds")
 	ErrFunctionRunFail = errors.New("failed to run function")
 	ErrUnauthorized    = errors.New("unauthorized: invalid API key")
 	ErrNotFound        = errors.New("not found")
+	ErrIndexNotFound   = errors.New("index not found")
 )
 
+// StatusError is returned when a request gets an unexpected HTTP status,
+// so callers can decide on the status code rather than the message.
+type StatusError struct {
+	Op         string
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("failed to %s with status %s: %s", e.Op, e.Status, e.Body)
+	}
+	return fmt.Sprintf("failed to %s with status %s", e.Op, e.Status)
+}
+
 // IsErrorType checks if an error is of a specific type
 func IsErrorType(err, target error) bool {
 	return errors.Is(err, target)
@@ -0,0 +1,214 @@
+package opperai
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// CompareOptions configures CompareEvaluations.
+type CompareOptions struct {
+	// Resamples is the number of bootstrap resamples (default 10000).
+	Resamples int
+	// Confidence is the confidence level of the interval (default 0.95).
+	Confidence float64
+	// Tolerance is the absolute difference below which two values are a tie.
+	Tolerance float64
+	// Seed makes the bootstrap deterministic.
+	Seed int64
+}
+
+// MinSignificancePairs is the number of paired records below which no
+// confidence interval or significance is computed: with a handful of pairs
+// that all move the same way the bootstrap reports p=0.
+const MinSignificancePairs = 10
+
+// DimensionComparison holds the paired comparison of one metric dimension.
+// Deltas are B minus A, so a positive delta means B scored higher.
+// Insufficient is set when there are fewer than MinSignificancePairs pairs;
+// the interval and p-value are then left at zero and Significant is false.
+type DimensionComparison struct {
+	Dimension    string  `json:"dimension"`
+	Pairs        int     `json:"pairs"`
+	MeanA        float64 `json:"mean_a"`
+	MeanB        float64 `json:"mean_b"`
+	Delta        float64 `json:"delta"`
+	Wins         int     `json:"wins"`
+	Losses       int     `json:"losses"`
+	Ties         int     `json:"ties"`
+	CILow        float64 `json:"ci_low"`
+	CIHigh       float64 `json:"ci_high"`
+	PValue       float64 `json:"p_value"`
+	Significant  bool    `json:"significant"`
+	Insufficient bool    `json:"insufficient_data,omitempty"`
+}
+
+// RecordRegression is a dataset entry that scored lower in B than in A.
+type RecordRegression struct {
+	DatasetEntryUUID string  `json:"dataset_entry_uuid"`
+	Dimension        string  `json:"dimension"`
+	ValueA           float64 `json:"value_a"`
+	ValueB           float64 `json:"value_b"`
+	Input            string  `json:"input"`
+}
+
+// EvaluationComparison is the result of comparing two evaluation runs.
+type EvaluationComparison struct {
+	EvaluationA string                `json:"evaluation_a"`
+	EvaluationB string                `json:"evaluation_b"`
+	Matched     int                   `json:"matched"`
+	OnlyInA     int                   `json:"only_in_a"`
+	OnlyInB     int                   `json:"only_in_b"`
+	Confidence  float64               `json:"confidence"`
+	Dimensions  []DimensionComparison `json:"dimensions"`
+	Regressions []RecordRegression    `json:"regressions"`
+}
+
+// CompareEvaluations aligns the records of two evaluations by dataset entry
+// and compares every dimension present in both, using a paired bootstrap to
+// estimate a confidence interval and p-value for the mean difference.
+func CompareEvaluations(a, b *Evaluation, opts *CompareOptions) *EvaluationComparison {
+	o := CompareOptions{Resamples: 10000, Confidence: 0.95, Tolerance: 1e-9}
+	if opts != nil {
+		if opts.Resamples > 0 {
+			o.Resamples = opts.Resamples
+		}
+		if opts.Confidence > 0 && opts.Confidence < 1 {
+			o.Confidence = opts.Confidence
+		}
+		if opts.Tolerance > 0 {
+			o.Tolerance = opts.Tolerance
+		}
+		o.Seed = opts.Seed
+	}
+
+	result := &EvaluationComparison{
+		EvaluationA: a.EvaluationUUID,
+		EvaluationB: b.EvaluationUUID,
+		Confidence:  o.Confidence,
+	}
+
+	recordsB := make(map[string]EvaluationRecord, len(b.Records))
+	for _, record := range b.Records {
+		recordsB[record.DatasetEntryUUID] = record
+	}
+
+	type pair struct {
+		a, b EvaluationRecord
+	}
+	var pairs []pair
+	for _, record := range a.Records {
+		if other, ok := recordsB[record.DatasetEntryUUID]; ok {
+			pairs = append(pairs, pair{record, other})
+		}
+	}
+	result.Matched = len(pairs)
+	result.OnlyInA = len(a.Records) - len(pairs)
+	result.OnlyInB = len(b.Records) - len(pairs)
+
+	rng := rand.New(rand.NewSource(o.Seed))
+	for _, dim := range sharedDimensions(a, b) {
+		cmp := DimensionComparison{Dimension: dim}
+		var diffs []float64
+		var sumA, sumB float64
+
+		for _, p := range pairs {
+			ma, okA := p.a.Metrics[dim]
+			mb, okB := p.b.Metrics[dim]
+			if !okA || !okB {
+				continue
+			}
+
+			sumA += ma.Value
+			sumB += mb.Value
+			d := mb.Value - ma.Value
+			diffs = append(diffs, d)
+
+			switch {
+			case d > o.Tolerance:
+				cmp.Wins++
+			case d < -o.Tolerance:
+				cmp.Losses++
+				result.Regressions = append(result.Regressions, RecordRegression{
+					DatasetEntryUUID: p.a.DatasetEntryUUID,
+					Dimension:        dim,
+					ValueA:           ma.Value,
+					ValueB:           mb.Value,
+					Input:            p.a.Input,
+				})
+			default:
+				cmp.Ties++
+			}
+		}
+
+		cmp.Pairs = len(diffs)
+		if cmp.Pairs > 0 {
+			n := float64(cmp.Pairs)
+			cmp.MeanA = sumA / n
+			cmp.MeanB = sumB / n
+			cmp.Delta = cmp.MeanB - cmp.MeanA
+		}
+		if cmp.Pairs >= MinSignificancePairs {
+			cmp.CILow, cmp.CIHigh, cmp.PValue = pairedBootstrap(diffs, o.Resamples, o.Confidence, rng)
+			cmp.Significant = cmp.PValue < 1-o.Confidence
+		} else if cmp.Pairs > 0 {
+			cmp.Insufficient = true
+		}
+		result.Dimensions = append(result.Dimensions, cmp)
+	}
+
+	sort.SliceStable(result.Regressions, func(i, j int) bool {
+		ri, rj := result.Regressions[i], result.Regressions[j]
+		return ri.ValueA-ri.ValueB > rj.ValueA-rj.ValueB
+	})
+
+	return result
+}
+
+// sharedDimensions returns the dimensions reported by both evaluations, in
+// the order of the first one
+func sharedDimensions(a, b *Evaluation) []string {
+	inB := make(map[string]bool, len(b.Dimensions))
+	for _, dim := range b.Dimensions {
+		inB[dim] = true
+	}
+
+	var dims []string
+	for _, dim := range a.Dimensions {
+		if inB[dim] {
+			dims = append(dims, dim)
+		}
+	}
+	return dims
+}
+
+// pairedBootstrap resamples the paired differences and returns the
+// percentile confidence interval of their mean and a two-sided p-value for
+// the hypothesis that the mean difference is zero.
+func pairedBootstrap(diffs []float64, resamples int, confidence float64, rng *rand.Rand) (float64, float64, float64) {
+	n := len(diffs)
+	means := make([]float64, resamples)
+	var below, above int
+	for i := range means {
+		var sum float64
+		for j := 0; j < n; j++ {
+			sum += diffs[rng.Intn(n)]
+		}
+		mean := sum / float64(n)
+		means[i] = mean
+		if mean <= 0 {
+			below++
+		}
+		if mean >= 0 {
+			above++
+		}
+	}
+	sort.Float64s(means)
+
+	alpha := (1 - confidence) / 2
+	low := means[int(math.Floor(alpha*float64(resamples-1)))]
+	high := means[int(math.Ceil((1-alpha)*float64(resamples-1)))]
+
+	p := 2 * math.Min(float64(below), float64(above)) / float64(resamples)
+	return low, high, math.Min(p, 1)
+}
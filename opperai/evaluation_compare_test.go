@@ -0,0 +1,98 @@
+package opperai
+
+import (
+	"fmt"
+	"testing"
+)
+
+func makeEvaluation(uuid string, scores map[string]float64) *Evaluation {
+	eval := &Evaluation{
+		EvaluationUUID: uuid,
+		Dimensions:     []string{"opper.score"},
+	}
+	for entry, score := range scores {
+		eval.Records = append(eval.Records, EvaluationRecord{
+			DatasetEntryUUID: entry,
+			Metrics: map[string]EvaluationMetric{
+				"opper.score": {Dimension: "opper.score", Value: score},
+			},
+		})
+	}
+	return eval
+}
+
+func TestCompareEvaluations(t *testing.T) {
+	scoresA := map[string]float64{}
+	scoresB := map[string]float64{}
+	for i := 0; i < 30; i++ {
+		entry := fmt.Sprintf("e%d", i)
+		scoresA[entry] = 0.5
+		scoresB[entry] = 0.8
+	}
+	// One regression and one entry only present in A
+	scoresA["e0"], scoresB["e0"] = 0.9, 0.1
+	scoresA["only-a"] = 1
+
+	a := makeEvaluation("a", scoresA)
+	b := makeEvaluation("b", scoresB)
+	result := CompareEvaluations(a, b, &CompareOptions{Resamples: 2000, Seed: 1})
+
+	if result.Matched != 30 || result.OnlyInA != 1 || result.OnlyInB != 0 {
+		t.Errorf("expected 30 matched, 1 only in A, 0 only in B, got %d, %d, %d", result.Matched, result.OnlyInA, result.OnlyInB)
+	}
+	if len(result.Dimensions) != 1 {
+		t.Fatalf("expected 1 dimension, got %d", len(result.Dimensions))
+	}
+
+	dim := result.Dimensions[0]
+	if dim.Wins != 29 || dim.Losses != 1 || dim.Ties != 0 {
+		t.Errorf("expected 29/1/0 wins/losses/ties, got %d/%d/%d", dim.Wins, dim.Losses, dim.Ties)
+	}
+	if dim.Delta <= 0 {
+		t.Errorf("expected positive delta, got %f", dim.Delta)
+	}
+	if !dim.Significant {
+		t.Errorf("expected significant improvement, got p=%f", dim.PValue)
+	}
+	if dim.CILow > dim.Delta || dim.CIHigh < dim.Delta {
+		t.Errorf("expected delta %f within CI [%f, %f]", dim.Delta, dim.CILow, dim.CIHigh)
+	}
+
+	if len(result.Regressions) != 1 || result.Regressions[0].DatasetEntryUUID != "e0" {
+		t.Errorf("expected single regression on e0, got %+v", result.Regressions)
+	}
+}
+
+func TestCompareEvaluationsNoDifference(t *testing.T) {
+	scores := map[string]float64{}
+	for i := 0; i < 12; i++ {
+		scores[fmt.Sprintf("e%d", i)] = float64(i) / 12
+	}
+	result := CompareEvaluations(makeEvaluation("a", scores), makeEvaluation("b", scores), nil)
+
+	dim := result.Dimensions[0]
+	if dim.Ties != 12 || dim.Delta != 0 {
+		t.Errorf("expected 12 ties and zero delta, got %d ties and delta %f", dim.Ties, dim.Delta)
+	}
+	if dim.Significant || dim.PValue != 1 {
+		t.Errorf("expected non-significant result with p=1, got p=%f", dim.PValue)
+	}
+	if len(result.Regressions) != 0 {
+		t.Errorf("expected no regressions, got %d", len(result.Regressions))
+	}
+}
+
+func TestCompareEvaluationsInsufficientPairs(t *testing.T) {
+	// Every pair improves, which a bootstrap over so few pairs reports as p=0
+	a := makeEvaluation("a", map[string]float64{"e1": 0.1, "e2": 0.2, "e3": 0.3})
+	b := makeEvaluation("b", map[string]float64{"e1": 0.9, "e2": 0.9, "e3": 0.9})
+	result := CompareEvaluations(a, b, &CompareOptions{Seed: 1})
+
+	dim := result.Dimensions[0]
+	if !dim.Insufficient || dim.Significant {
+		t.Errorf("expected insufficient, non-significant result, got %+v", dim)
+	}
+	if dim.Wins != 3 || dim.Delta <= 0 {
+		t.Errorf("expected 3 wins and a positive delta, got %+v", dim)
+	}
+}
@@ -0,0 +1,158 @@
+package opperai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Filter operations supported by index queries.
+const (
+	FilterEqual          = "="
+	FilterNotEqual       = "!="
+	FilterGreater        = ">"
+	FilterGreaterOrEqual = ">="
+	FilterLess           = "<"
+	FilterLessOrEqual    = "<="
+	FilterIn             = "in"
+	FilterNotIn          = "nin"
+)
+
+var filterListRe = regexp.MustCompile(`(?i)^([\w.\-]+)\s+(not\s+in|nin|in)\s+(.+)$`)
+
+// ParseFilter parses a filter expression such as "lang=go", "year>=2023",
+// "status!=draft" or "lang in go,rust". Values are read as numbers or
+// booleans when possible; quote a value to keep it a string ("zip='01234'").
+func ParseFilter(expr string) (Filter, error) {
+	expr = strings.TrimSpace(expr)
+
+	if m := filterListRe.FindStringSubmatch(expr); m != nil {
+		op := FilterIn
+		if !strings.EqualFold(m[2], "in") {
+			op = FilterNotIn
+		}
+		values, err := parseFilterList(m[3])
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid filter %q: %w", expr, err)
+		}
+		return Filter{Field: m[1], Operation: op, Value: values}, nil
+	}
+
+	i := strings.IndexAny(expr, "!<>=")
+	if i <= 0 {
+		return Filter{}, fmt.Errorf("invalid filter %q: expected <field><op><value> with op one of =, !=, >, >=, <, <=, in, not in", expr)
+	}
+
+	field := strings.TrimSpace(expr[:i])
+	rest := expr[i:]
+	var op string
+	switch {
+	case strings.HasPrefix(rest, "=="):
+		op, rest = FilterEqual, rest[2:]
+	case strings.HasPrefix(rest, "!="), strings.HasPrefix(rest, ">="), strings.HasPrefix(rest, "<="):
+		op, rest = rest[:2], rest[2:]
+	case strings.HasPrefix(rest, "="), strings.HasPrefix(rest, ">"), strings.HasPrefix(rest, "<"):
+		op, rest = rest[:1], rest[1:]
+	default:
+		return Filter{}, fmt.Errorf("invalid filter %q: unknown operator", expr)
+	}
+
+	return Filter{Field: field, Operation: op, Value: parseFilterValue(rest)}, nil
+}
+
+// ParseFilters parses filter expressions and JSON. An argument starting with
+// "[" is a JSON list of filters; one starting with "{" is either a single
+// filter object or a map of field to value, read as equality filters.
+// Anything else is parsed with ParseFilter.
+func ParseFilters(args []string) ([]Filter, error) {
+	var filters []Filter
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		switch {
+		case arg == "":
+			continue
+		case strings.HasPrefix(arg, "["):
+			var list []Filter
+			if err := json.Unmarshal([]byte(arg), &list); err != nil {
+				return nil, fmt.Errorf("invalid filter JSON: %w", err)
+			}
+			for _, f := range list {
+				if f.Field == "" || f.Operation == "" {
+					return nil, fmt.Errorf("invalid filter JSON: each filter needs a field and an operation")
+				}
+			}
+			filters = append(filters, list...)
+		case strings.HasPrefix(arg, "{"):
+			var raw map[string]interface{}
+			if err := json.Unmarshal([]byte(arg), &raw); err != nil {
+				return nil, fmt.Errorf("invalid filter JSON: %w", err)
+			}
+			if _, ok := raw["field"]; ok {
+				var f Filter
+				if err := json.Unmarshal([]byte(arg), &f); err != nil {
+					return nil, fmt.Errorf("invalid filter JSON: %w", err)
+				}
+				if f.Operation == "" {
+					f.Operation = FilterEqual
+				}
+				filters = append(filters, f)
+				continue
+			}
+			// Sorted for a stable request body
+			fields := make([]string, 0, len(raw))
+			for field := range raw {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			for _, field := range fields {
+				filters = append(filters, Filter{Field: field, Operation: FilterEqual, Value: raw[field]})
+			}
+		default:
+			f, err := ParseFilter(arg)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, f)
+		}
+	}
+	return filters, nil
+}
+
+func parseFilterList(s string) ([]interface{}, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") {
+		var values []interface{}
+		if err := json.Unmarshal([]byte(s), &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+
+	parts := strings.Split(s, ",")
+	values := make([]interface{}, 0, len(parts))
+	for _, part := range parts {
+		values = append(values, parseFilterValue(part))
+	}
+	return values, nil
+}
+
+// parseFilterValue reads quoted values as strings and bare values as
+// booleans or numbers when they parse as such
+func parseFilterValue(s string) interface{} {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	if s == "true" || s == "false" {
+		return s == "true"
+	}
+	// ParseFloat also accepts "inf" and "nan", which cannot be sent as JSON
+	if n, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(n, 0) && !math.IsNaN(n) {
+		return n
+	}
+	return s
+}
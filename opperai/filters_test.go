@@ -0,0 +1,97 @@
+package opperai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    Filter
+		wantErr bool
+	}{
+		{name: "equal string", expr: "lang=go", want: Filter{Field: "lang", Operation: "=", Value: "go"}},
+		{name: "double equal", expr: "lang == go", want: Filter{Field: "lang", Operation: "=", Value: "go"}},
+		{name: "greater or equal number", expr: "year>=2023", want: Filter{Field: "year", Operation: ">=", Value: float64(2023)}},
+		{name: "less than", expr: "score<0.5", want: Filter{Field: "score", Operation: "<", Value: 0.5}},
+		{name: "not equal bool", expr: "draft!=true", want: Filter{Field: "draft", Operation: "!=", Value: true}},
+		{name: "quoted number stays string", expr: "zip='01234'", want: Filter{Field: "zip", Operation: "=", Value: "01234"}},
+		{name: "in list", expr: "lang in go,rust", want: Filter{Field: "lang", Operation: "in", Value: []interface{}{"go", "rust"}}},
+		{name: "not in json list", expr: "year not in [2020, 2021]", want: Filter{Field: "year", Operation: "nin", Value: []interface{}{float64(2020), float64(2021)}}},
+		{name: "nested field", expr: "meta.tenant=acme", want: Filter{Field: "meta.tenant", Operation: "=", Value: "acme"}},
+		{name: "nan stays string", expr: "status=nan", want: Filter{Field: "status", Operation: "=", Value: "nan"}},
+		{name: "inf stays string", expr: "name=inf", want: Filter{Field: "name", Operation: "=", Value: "inf"}},
+		{name: "infinity stays string", expr: "name=-Infinity", want: Filter{Field: "name", Operation: "=", Value: "-Infinity"}},
+		{name: "overflow stays string", expr: "id=1e999", want: Filter{Field: "id", Operation: "=", Value: "1e999"}},
+		{name: "list with nan", expr: "status in ok,NaN", want: Filter{Field: "status", Operation: "in", Value: []interface{}{"ok", "NaN"}}},
+		{name: "missing field", expr: "=go", wantErr: true},
+		{name: "missing operator", expr: "lang", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilter(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFilter() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []Filter
+		wantErr bool
+	}{
+		{
+			name: "expressions and json list",
+			args: []string{"lang=go", `[{"field":"year","operation":">","value":2020}]`},
+			want: []Filter{
+				{Field: "lang", Operation: "=", Value: "go"},
+				{Field: "year", Operation: ">", Value: float64(2020)},
+			},
+		},
+		{
+			name: "single filter object",
+			args: []string{`{"field":"tenant","value":"acme"}`},
+			want: []Filter{{Field: "tenant", Operation: "=", Value: "acme"}},
+		},
+		{
+			name: "map of equality filters",
+			args: []string{`{"b":1,"a":"x"}`},
+			want: []Filter{
+				{Field: "a", Operation: "=", Value: "x"},
+				{Field: "b", Operation: "=", Value: float64(1)},
+			},
+		},
+		{
+			name: "empty object",
+			args: []string{"{}"},
+			want: nil,
+		},
+		{
+			name:    "list entry without operation",
+			args:    []string{`[{"field":"a","value":1}]`},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilters(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFilters() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,7 +7,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/google/go-querystring/query"
 )
 
 type FunctionsClient struct {
@@ -66,8 +72,26 @@ func (c *FunctionsClient) Delete(ctx context.Context, id string, path string) er
 	return nil
 }
 
+// List returns all functions, following pagination until exhausted.
 func (c *FunctionsClient) List(ctx context.Context) ([]FunctionDescription, error) {
-	resp, err := c.client.DoRequest(ctx, "GET", "/v1/functions?limit=1000", nil)
+	return c.Iter(ctx, nil, nil).All()
+}
+
+// ListPage returns a single page of functions starting at offset.
+func (c *FunctionsClient) ListPage(ctx context.Context, params *FunctionListParams, offset, limit int) (*FunctionsResponse, error) {
+	values := url.Values{}
+	if params != nil {
+		var err error
+		values, err = query.Values(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode query parameters: %w", err)
+		}
+	}
+	values.Set("offset", strconv.Itoa(offset))
+	values.Set("limit", strconv.Itoa(limit))
+
+	endpoint := fmt.Sprintf("/v1/functions?%s", values.Encode())
+	resp, err := c.client.DoRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -77,19 +101,29 @@ func (c *FunctionsClient) List(ctx context.Context) ([]FunctionDescription, erro
 		return nil, fmt.Errorf("failed to list functions with status %s", resp.Status)
 	}
 
-	// Create a struct to match the API response structure
-	var response struct {
-		Meta struct {
-			TotalCount int `json:"total_count"`
-		} `json:"meta"`
-		Data []FunctionDescription `json:"data"`
-	}
-
+	var response FunctionsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	return response.Data, nil
+	return &response, nil
+}
+
+// Iter returns an iterator over the functions in the organization matching params.
+func (c *FunctionsClient) Iter(ctx context.Context, params *FunctionListParams, opts *ListOptions) *Iterator[FunctionDescription] {
+	offset := 0
+	return newIterator(ctx, opts, func(ctx context.Context, pageSize int) (*page[FunctionDescription], error) {
+		response, err := c.ListPage(ctx, params, offset, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		offset += len(response.Data)
+		return &page[FunctionDescription]{
+			items: response.Data,
+			more:  offset < response.Meta.TotalCount,
+			total: response.Meta.TotalCount,
+		}, nil
+	})
 }
 
 func (c *FunctionsClient) GetByPath(ctx context.Context, functionPath string) (*FunctionDescription, error) {
@@ -150,9 +184,38 @@ func (c *FunctionsClient) Chat(ctx context.Context, functionPath string, message
 }
 
 func (c *FunctionsClient) ListEvaluations(ctx context.Context, functionUUID string, limit int) (*EvaluationsResponse, error) {
-	endpoint := fmt.Sprintf("/api/v1/functions/%s/evaluations", functionUUID)
+	return c.listEvaluations(ctx, functionUUID, 0, limit)
+}
+
+// IterEvaluations returns an iterator over all evaluations of a function.
+func (c *FunctionsClient) IterEvaluations(ctx context.Context, functionUUID string, opts *ListOptions) *Iterator[Evaluation] {
+	offset := 0
+	return newIterator(ctx, opts, func(ctx context.Context, pageSize int) (*page[Evaluation], error) {
+		response, err := c.listEvaluations(ctx, functionUUID, offset, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		offset += len(response.Data)
+		return &page[Evaluation]{
+			items: response.Data,
+			more:  offset < response.Meta.TotalCount,
+			total: response.Meta.TotalCount,
+		}, nil
+	})
+}
+
+func (c *FunctionsClient) listEvaluations(ctx context.Context, functionUUID string, offset, limit int) (*EvaluationsResponse, error) {
+	params := url.Values{}
+	if offset > 0 {
+		params.Set("offset", strconv.Itoa(offset))
+	}
 	if limit > 0 {
-		endpoint = fmt.Sprintf("%s?limit=%d", endpoint, limit)
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/functions/%s/evaluations", functionUUID)
+	if len(params) > 0 {
+		endpoint = fmt.Sprintf("%s?%s", endpoint, params.Encode())
 	}
 
 	resp, err := c.client.DoRequest(ctx, "GET", endpoint, nil)
@@ -173,26 +236,204 @@ func (c *FunctionsClient) ListEvaluations(ctx context.Context, functionUUID stri
 	return &evaluations, nil
 }
 
-func (c *FunctionsClient) CreateEvaluation(ctx context.Context, datasetUUID string) error {
-	data := map[string]string{
+// CreateEvaluation starts an evaluation of the given dataset. A non-nil
+// override runs the evaluation with a different model, instructions or
+// few-shot count without changing the function itself.
+func (c *FunctionsClient) CreateEvaluation(ctx context.Context, datasetUUID string, override *FunctionOverride) (*Evaluation, error) {
+	data := map[string]interface{}{
 		"dataset_uuid": datasetUUID,
 	}
+	if override != nil && !override.IsZero() {
+		data["function_override"] = override
+	}
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("error marshaling request: %w", err)
+		return nil, fmt.Errorf("error marshaling request: %w", err)
 	}
 
 	resp, err := c.client.DoRequest(ctx, "POST", "/api/v1/evaluations", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create evaluation with status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("failed to create evaluation with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	return nil
+	var evaluation Evaluation
+	if err := json.NewDecoder(resp.Body).Decode(&evaluation); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &evaluation, nil
+}
+
+func (c *FunctionsClient) GetEvaluation(ctx context.Context, evaluationUUID string) (*Evaluation, error) {
+	endpoint := fmt.Sprintf("/api/v1/evaluations/%s", evaluationUUID)
+	resp, err := c.client.DoRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("evaluation not found: %s", evaluationUUID)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get evaluation with status %s", resp.Status)
+	}
+
+	var evaluation Evaluation
+	if err := json.NewDecoder(resp.Body).Decode(&evaluation); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &evaluation, nil
+}
+
+// WaitForEvaluation polls an evaluation every interval until it reaches a
+// terminal state or ctx is done. onUpdate, if not nil, is called with every
+// polled state.
+func (c *FunctionsClient) WaitForEvaluation(ctx context.Context, evaluationUUID string, interval time.Duration, onUpdate func(*Evaluation)) (*Evaluation, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		evaluation, err := c.GetEvaluation(ctx, evaluationUUID)
+		if err != nil {
+			return nil, err
+		}
+		if onUpdate != nil {
+			onUpdate(evaluation)
+		}
+		if evaluation.Status.Done() {
+			return evaluation, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return evaluation, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *FunctionsClient) Update(ctx context.Context, functionUUID string, update *FunctionUpdate) (*FunctionDescription, error) {
+	return c.patch(ctx, functionUUID, update)
+}
+
+// patch sends body as a PATCH of the function and returns the result
+func (c *FunctionsClient) patch(ctx context.Context, functionUUID string, body interface{}) (*FunctionDescription, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/functions/%s", functionUUID)
+	resp, err := c.client.DoRequest(ctx, "PATCH", endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("function not found: %s", functionUUID)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to update function with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var updated FunctionDescription
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// ListRevisions returns all stored revisions of a function, oldest first.
+func (c *FunctionsClient) ListRevisions(ctx context.Context, functionUUID string) ([]FunctionRevision, error) {
+	endpoint := fmt.Sprintf("/api/v1/functions/%s/revisions", functionUUID)
+	resp, err := c.client.DoRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("function not found: %s", functionUUID)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list revisions with status %s", resp.Status)
+	}
+
+	var revisions []FunctionRevision
+	if err := json.NewDecoder(resp.Body).Decode(&revisions); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+
+	return revisions, nil
+}
+
+func (c *FunctionsClient) GetRevision(ctx context.Context, functionUUID string, revision int) (*FunctionRevision, error) {
+	endpoint := fmt.Sprintf("/api/v1/functions/%s/revisions/%d", functionUUID, revision)
+	resp, err := c.client.DoRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("revision %d not found", revision)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get revision %d with status %s", revision, resp.Status)
+	}
+
+	var rev FunctionRevision
+	if err := json.NewDecoder(resp.Body).Decode(&rev); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &rev, nil
+}
+
+// functionRestore sets every restorable field, including empty ones, unlike
+// FunctionUpdate which leaves empty fields untouched
+type functionRestore struct {
+	Instructions string                 `json:"instructions"`
+	Description  string                 `json:"description"`
+	Model        string                 `json:"model"`
+	InputSchema  map[string]interface{} `json:"input_schema"`
+	OutputSchema map[string]interface{} `json:"out_schema"`
+}
+
+// Rollback restores the instructions, description, model and schemas of the
+// given revision, clearing fields that were empty in it. The server records
+// the restored state as a new revision.
+func (c *FunctionsClient) Rollback(ctx context.Context, functionUUID string, revision int) (*FunctionDescription, error) {
+	rev, err := c.GetRevision(ctx, functionUUID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.patch(ctx, functionUUID, functionRestore{
+		Instructions: rev.Instructions,
+		Description:  rev.Description,
+		Model:        rev.Model,
+		InputSchema:  rev.InputSchema,
+		OutputSchema: rev.OutputSchema,
+	})
 }
@@ -3,10 +3,13 @@ package opperai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 )
 
 const (
@@ -374,3 +377,386 @@ func TestFunctionChat(t *testing.T) {
 		})
 	}
 }
+
+func TestListFunctionRevisions(t *testing.T) {
+	tests := []struct {
+		name         string
+		functionUUID string
+		response     []FunctionRevision
+		statusCode   int
+		wantErr      bool
+	}{
+		{
+			name:         "successful list",
+			functionUUID: "func-uuid",
+			response: []FunctionRevision{
+				{Revision: 2, Instructions: "second"},
+				{Revision: 1, Instructions: "first"},
+			},
+			statusCode: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:         "function not found",
+			functionUUID: "nonexistent",
+			statusCode:   http.StatusNotFound,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("expected GET request, got %s", r.Method)
+				}
+				expectedPath := fmt.Sprintf("%s/%s/revisions", functionsBasePath, tt.functionUUID)
+				if r.URL.Path != expectedPath {
+					t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+				}
+
+				w.WriteHeader(tt.statusCode)
+				if tt.statusCode == http.StatusOK {
+					json.NewEncoder(w).Encode(tt.response)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL)
+			revisions, err := client.Functions.ListRevisions(context.Background(), tt.functionUUID)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListRevisions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err == nil {
+				if len(revisions) != len(tt.response) {
+					t.Fatalf("expected %d revisions, got %d", len(tt.response), len(revisions))
+				}
+				for i := 1; i < len(revisions); i++ {
+					if revisions[i-1].Revision > revisions[i].Revision {
+						t.Errorf("expected revisions sorted ascending, got %d before %d", revisions[i-1].Revision, revisions[i].Revision)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRollbackFunction(t *testing.T) {
+	tests := []struct {
+		name         string
+		functionUUID string
+		revision     int
+		response     FunctionRevision
+		statusCode   int
+		wantErr      bool
+	}{
+		{
+			name:         "successful rollback",
+			functionUUID: "func-uuid",
+			revision:     3,
+			response: FunctionRevision{
+				Revision:     3,
+				Instructions: "old instructions",
+				Model:        "gpt-4o",
+			},
+			statusCode: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:         "revision not found",
+			functionUUID: "func-uuid",
+			revision:     99,
+			statusCode:   http.StatusNotFound,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patched bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					expectedPath := fmt.Sprintf("%s/%s/revisions/%d", functionsBasePath, tt.functionUUID, tt.revision)
+					if r.URL.Path != expectedPath {
+						t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+					}
+					w.WriteHeader(tt.statusCode)
+					if tt.statusCode == http.StatusOK {
+						json.NewEncoder(w).Encode(tt.response)
+					}
+				case http.MethodPatch:
+					patched = true
+					expectedPath := fmt.Sprintf("%s/%s", functionsBasePath, tt.functionUUID)
+					if r.URL.Path != expectedPath {
+						t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+					}
+
+					var body map[string]interface{}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("failed to decode request body: %v", err)
+					}
+					// Empty fields of the revision must be sent to clear them
+					for _, field := range []string{"instructions", "description", "model", "input_schema", "out_schema"} {
+						if _, ok := body[field]; !ok {
+							t.Errorf("expected %s in the update, got %v", field, body)
+						}
+					}
+					var update FunctionUpdate
+					data, _ := json.Marshal(body)
+					json.Unmarshal(data, &update)
+					if update.Instructions != tt.response.Instructions {
+						t.Errorf("expected instructions %q, got %q", tt.response.Instructions, update.Instructions)
+					}
+					if update.Model != tt.response.Model {
+						t.Errorf("expected model %q, got %q", tt.response.Model, update.Model)
+					}
+
+					w.WriteHeader(http.StatusOK)
+					json.NewEncoder(w).Encode(FunctionDescription{
+						UUID:         tt.functionUUID,
+						Instructions: update.Instructions,
+						Revision:     tt.revision + 1,
+					})
+				default:
+					t.Errorf("unexpected %s request", r.Method)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL)
+			function, err := client.Functions.Rollback(context.Background(), tt.functionUUID, tt.revision)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Rollback() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && patched {
+				t.Error("Rollback() should not update the function when the revision is missing")
+			}
+			if err == nil && function.Instructions != tt.response.Instructions {
+				t.Errorf("expected instructions %q, got %q", tt.response.Instructions, function.Instructions)
+			}
+		})
+	}
+}
+
+func TestListFunctionsPaginated(t *testing.T) {
+	const total = 250
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		response := FunctionsResponse{}
+		response.Meta.TotalCount = total
+		for i := offset; i < offset+limit && i < total; i++ {
+			response.Data = append(response.Data, FunctionDescription{Path: fmt.Sprintf("fn/%d", i)})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	functions, err := client.Functions.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() unexpected error = %v", err)
+	}
+
+	if len(functions) != total {
+		t.Errorf("expected %d functions, got %d", total, len(functions))
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+	if functions[total-1].Path != fmt.Sprintf("fn/%d", total-1) {
+		t.Errorf("expected last path fn/%d, got %s", total-1, functions[total-1].Path)
+	}
+}
+
+func TestListFunctionsWithParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		expected := map[string]string{
+			"project":     "support",
+			"model":       "gpt-4o",
+			"has_dataset": "true",
+			"sort":        "-revision",
+			"offset":      "0",
+			"limit":       "50",
+		}
+		for key, want := range expected {
+			if got := q.Get(key); got != want {
+				t.Errorf("expected %s=%s, got %s", key, want, got)
+			}
+		}
+		if got := q["metadata"]; len(got) != 2 || got[0] != "team=ml" || got[1] != "tier=prod" {
+			t.Errorf("expected metadata [team=ml tier=prod], got %v", got)
+		}
+
+		json.NewEncoder(w).Encode(FunctionsResponse{})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	_, err := client.Functions.ListPage(context.Background(), &FunctionListParams{
+		Project:    "support",
+		Model:      "gpt-4o",
+		HasDataset: true,
+		Metadata:   []string{"team=ml", "tier=prod"},
+		Sort:       "-revision",
+	}, 0, 50)
+	if err != nil {
+		t.Fatalf("ListPage() unexpected error = %v", err)
+	}
+}
+
+func TestCreateEvaluation(t *testing.T) {
+	fewShot := 3
+	tests := []struct {
+		name       string
+		override   *FunctionOverride
+		statusCode int
+		wantErr    bool
+	}{
+		{
+			name:       "successful create",
+			statusCode: http.StatusCreated,
+			wantErr:    false,
+		},
+		{
+			name: "create with override",
+			override: &FunctionOverride{
+				Model:        "anthropic/claude-3.5-sonnet",
+				Instructions: "new instructions",
+				FewShotCount: &fewShot,
+			},
+			statusCode: http.StatusCreated,
+			wantErr:    false,
+		},
+		{
+			name:       "server error",
+			statusCode: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("expected POST request, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/v1/evaluations" {
+					t.Errorf("expected path %s, got %s", "/api/v1/evaluations", r.URL.Path)
+				}
+
+				var body struct {
+					DatasetUUID      string            `json:"dataset_uuid"`
+					FunctionOverride *FunctionOverride `json:"function_override"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("failed to decode request body: %v", err)
+				}
+				if body.DatasetUUID != "ds-uuid" {
+					t.Errorf("expected dataset_uuid ds-uuid, got %v", body.DatasetUUID)
+				}
+				if tt.override == nil && body.FunctionOverride != nil {
+					t.Errorf("expected no function_override, got %+v", body.FunctionOverride)
+				}
+				if tt.override != nil {
+					if body.FunctionOverride == nil {
+						t.Error("expected function_override in request body")
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					if body.FunctionOverride.Model != tt.override.Model || body.FunctionOverride.Instructions != tt.override.Instructions {
+						t.Errorf("expected override %+v, got %+v", tt.override, body.FunctionOverride)
+					}
+					if body.FunctionOverride.FewShotCount == nil || *body.FunctionOverride.FewShotCount != fewShot {
+						t.Errorf("expected few_shot_count %d, got %v", fewShot, body.FunctionOverride.FewShotCount)
+					}
+				}
+
+				w.WriteHeader(tt.statusCode)
+				if tt.statusCode == http.StatusCreated {
+					json.NewEncoder(w).Encode(Evaluation{
+						EvaluationUUID: "eval-uuid",
+						Status:         EvaluationStatus{State: EvaluationStatePending},
+					})
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL)
+			evaluation, err := client.Functions.CreateEvaluation(context.Background(), "ds-uuid", tt.override)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateEvaluation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err == nil && evaluation.EvaluationUUID != "eval-uuid" {
+				t.Errorf("expected evaluation uuid eval-uuid, got %s", evaluation.EvaluationUUID)
+			}
+		})
+	}
+}
+
+func TestWaitForEvaluation(t *testing.T) {
+	states := []string{EvaluationStatePending, EvaluationStateRunning, EvaluationStateCompleted}
+
+	var polls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/evaluations/eval-uuid" {
+			t.Errorf("expected path %s, got %s", "/api/v1/evaluations/eval-uuid", r.URL.Path)
+		}
+		state := states[min(polls, len(states)-1)]
+		polls++
+		json.NewEncoder(w).Encode(Evaluation{
+			EvaluationUUID: "eval-uuid",
+			Status:         EvaluationStatus{State: state},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+
+	var updates int
+	evaluation, err := client.Functions.WaitForEvaluation(context.Background(), "eval-uuid", time.Millisecond, func(*Evaluation) {
+		updates++
+	})
+	if err != nil {
+		t.Fatalf("WaitForEvaluation() unexpected error = %v", err)
+	}
+
+	if evaluation.Status.State != EvaluationStateCompleted {
+		t.Errorf("expected state %s, got %s", EvaluationStateCompleted, evaluation.Status.State)
+	}
+	if polls != len(states) || updates != len(states) {
+		t.Errorf("expected %d polls and updates, got %d polls and %d updates", len(states), polls, updates)
+	}
+}
+
+func TestWaitForEvaluationContextDone(t *testing.T) {
+	server := httptest.NewServer(MockJSONResponse(http.StatusOK, Evaluation{
+		EvaluationUUID: "eval-uuid",
+		Status:         EvaluationStatus{State: EvaluationStateRunning},
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Functions.WaitForEvaluation(ctx, "eval-uuid", time.Millisecond, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package opperai
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+func documentPath(name, key string) string {
+	return fmt.Sprintf("/v1/indexes/by-name/%s/documents/%s", name, url.PathEscape(key))
+}
+
+// GetDocument returns the document stored under key.
+func (c *IndexesClient) GetDocument(name string, key string) (*Document, error) {
+	resp, err := c.client.DoRequest(context.Background(), "GET", documentPath(name, key), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("document not found in index %s: %s", name, key)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get document with status %s", resp.Status)
+	}
+
+	var doc Document
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &doc, nil
+}
+
+// DeleteDocument removes the document stored under key.
+func (c *IndexesClient) DeleteDocument(name string, key string) error {
+	resp, err := c.client.DoRequest(context.Background(), "DELETE", documentPath(name, key), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("document not found in index %s: %s", name, key)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete document with status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// DeleteDocuments removes every document matching all filters and returns
+// the number of documents deleted. At least one filter is required.
+func (c *IndexesClient) DeleteDocuments(name string, filters []Filter) (int, error) {
+	if len(filters) == 0 {
+		return 0, fmt.Errorf("at least one filter is required")
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"filters": filters})
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.client.DoRequest(context.Background(), "POST", fmt.Sprintf("/v1/indexes/by-name/%s/documents/delete", name), bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, fmt.Errorf("%w: %s", ErrIndexNotFound, name)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("failed to delete documents with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Deleted int `json:"deleted"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return result.Deleted, nil
+}
+
+// ListDocuments returns a single page of documents in an index.
+func (c *IndexesClient) ListDocuments(name string, offset, limit int) (*DocumentsResponse, error) {
+	endpoint := fmt.Sprintf("/v1/indexes/by-name/%s/documents?offset=%d&limit=%d", name, offset, limit)
+	resp, err := c.client.DoRequest(context.Background(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrIndexNotFound, name)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list documents with status %s", resp.Status)
+	}
+
+	var response DocumentsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &response, nil
+}
+
+// IterDocuments returns an iterator over all documents in an index.
+func (c *IndexesClient) IterDocuments(name string, opts *ListOptions) *Iterator[Document] {
+	offset := 0
+	return newIterator(context.Background(), opts, func(ctx context.Context, pageSize int) (*page[Document], error) {
+		response, err := c.ListDocuments(name, offset, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		offset += len(response.Data)
+		return &page[Document]{
+			items: response.Data,
+			more:  offset < response.Meta.TotalCount,
+			total: response.Meta.TotalCount,
+		}, nil
+	})
+}
+
+// AddBatch adds several documents in one request. Documents with an existing
+// key replace the stored document.
+func (c *IndexesClient) AddBatch(name string, docs []Document) error {
+	data, err := json.Marshal(map[string]interface{}{"documents": docs})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.DoRequest(context.Background(), "POST", fmt.Sprintf("/v1/indexes/index/by-name/%s/batch", name), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrIndexNotFound, name)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to add documents with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// ReadDocuments parses one document per line from JSONL. Each line is an
+// object with "content" and optional "key" and "metadata" fields. A missing
+// key is derived from the content, so re-importing the same file replaces
+// documents instead of duplicating them.
+func ReadDocuments(r io.Reader) ([]Document, error) {
+	var docs []Document
+	err := ScanJSONL(r, func(lineNo int, doc Document) error {
+		if doc.Content == "" {
+			return fmt.Errorf("missing content")
+		}
+		if doc.Key == "" {
+			sum := sha256.Sum256([]byte(doc.Content))
+			doc.Key = hex.EncodeToString(sum[:8])
+		}
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// WriteDocumentsJSONL writes documents as one JSON object per line.
+func WriteDocumentsJSONL(w io.Writer, docs []Document) error {
+	enc := json.NewEncoder(w)
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package opperai
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGetDocument(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "found", key: "guide/intro#1", statusCode: http.StatusOK},
+		{name: "not found", key: "missing", statusCode: http.StatusNotFound, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("expected GET request, got %s", r.Method)
+				}
+				expectedPath := indexesByName + "/docs/documents/" + tt.key
+				if r.URL.Path != expectedPath {
+					t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+				}
+				w.WriteHeader(tt.statusCode)
+				if tt.statusCode == http.StatusOK {
+					json.NewEncoder(w).Encode(Document{Key: tt.key, Content: "hello"})
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL)
+			doc, err := client.Indexes.GetDocument("docs", tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (doc.Key != tt.key || doc.Content != "hello") {
+				t.Errorf("unexpected document %+v", doc)
+			}
+		})
+	}
+}
+
+func TestDeleteDocuments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != indexesByName+"/docs/documents/delete" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Filters []Filter `json:"filters"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Filters) != 1 || body.Filters[0].Field != "tenant" {
+			t.Errorf("unexpected filters %+v", body.Filters)
+		}
+		json.NewEncoder(w).Encode(map[string]int{"deleted": 3})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	if _, err := client.Indexes.DeleteDocuments("docs", nil); err == nil {
+		t.Error("expected error without filters")
+	}
+
+	deleted, err := client.Indexes.DeleteDocuments("docs", []Filter{{Field: "tenant", Operation: FilterEqual, Value: "acme"}})
+	if err != nil {
+		t.Fatalf("DeleteDocuments() error = %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("expected 3 deleted, got %d", deleted)
+	}
+}
+
+func TestIterDocuments(t *testing.T) {
+	const total = 5
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var response DocumentsResponse
+		response.Meta.TotalCount = total
+		for i := offset; i < offset+limit && i < total; i++ {
+			response.Data = append(response.Data, Document{Key: strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	docs, err := client.Indexes.IterDocuments("docs", &ListOptions{PageSize: 2}).All()
+	if err != nil {
+		t.Fatalf("IterDocuments() error = %v", err)
+	}
+	if len(docs) != total || docs[4].Key != "4" {
+		t.Errorf("unexpected documents %+v", docs)
+	}
+}
+
+func TestAddBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/indexes/index/by-name/docs/batch" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body struct {
+			Documents []Document `json:"documents"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Documents) != 2 {
+			t.Errorf("expected 2 documents, got %d", len(body.Documents))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	err := client.Indexes.AddBatch("docs", []Document{{Key: "a", Content: "x"}, {Key: "b", Content: "y"}})
+	if err != nil {
+		t.Fatalf("AddBatch() error = %v", err)
+	}
+}
+
+func TestAddBatchIndexNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	err := client.Indexes.AddBatch("missing", []Document{{Key: "a", Content: "x"}})
+	if !errors.Is(err, ErrIndexNotFound) {
+		t.Fatalf("AddBatch() error = %v, want ErrIndexNotFound", err)
+	}
+	if err.Error() != "index not found: missing" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
+
+func TestReadDocuments(t *testing.T) {
+	input := `{"key":"a","content":"first","metadata":{"lang":"go"}}
+
+{"content":"second"}
+`
+	docs, err := ReadDocuments(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadDocuments() error = %v", err)
+	}
+	if len(docs) != 2 || docs[0].Metadata["lang"] != "go" {
+		t.Fatalf("unexpected documents %+v", docs)
+	}
+	if docs[1].Key == "" {
+		t.Error("expected a key derived from content")
+	}
+
+	again, _ := ReadDocuments(strings.NewReader(input))
+	if again[1].Key != docs[1].Key {
+		t.Error("expected derived keys to be stable")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDocumentsJSONL(&buf, docs); err != nil {
+		t.Fatal(err)
+	}
+	roundTrip, err := ReadDocuments(&buf)
+	if err != nil || len(roundTrip) != 2 || roundTrip[1].Key != docs[1].Key {
+		t.Errorf("round trip failed: %+v, %v", roundTrip, err)
+	}
+
+	if _, err := ReadDocuments(strings.NewReader(`{"key":"a"}`)); err == nil || err.Error() != "line 1: missing content" {
+		t.Errorf("expected missing content error on line 1, got %v", err)
+	}
+	if _, err := ReadDocuments(strings.NewReader("{\"content\":\"x\"}\n\n{oops")); err == nil || !strings.HasPrefix(err.Error(), "line 3: invalid JSON") {
+		t.Errorf("expected invalid JSON error on line 3, got %v", err)
+	}
+}
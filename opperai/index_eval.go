@@ -0,0 +1,279 @@
+package opperai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RetrievalQuery is a query with the documents that should be retrieved for
+// it. Relevant maps document keys to relevance grades; higher grades matter
+// more for nDCG.
+type RetrievalQuery struct {
+	ID       string
+	Query    string
+	Relevant map[string]float64
+	Filters  []Filter
+}
+
+// UnmarshalJSON accepts "relevant" (or "expected") as a list of keys, each
+// graded 1, or as an object of key to grade.
+func (q *RetrievalQuery) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID       string          `json:"id"`
+		Query    string          `json:"query"`
+		Relevant json.RawMessage `json:"relevant"`
+		Expected json.RawMessage `json:"expected"`
+		Filters  []Filter        `json:"filters"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	relevant := raw.Relevant
+	if len(relevant) == 0 {
+		relevant = raw.Expected
+	}
+
+	q.ID, q.Query, q.Filters = raw.ID, raw.Query, raw.Filters
+	q.Relevant = map[string]float64{}
+	if len(relevant) == 0 {
+		return nil
+	}
+
+	var keys []string
+	if err := json.Unmarshal(relevant, &keys); err == nil {
+		for _, key := range keys {
+			q.Relevant[key] = 1
+		}
+		return nil
+	}
+	if err := json.Unmarshal(relevant, &q.Relevant); err != nil {
+		return fmt.Errorf("relevant must be a list of keys or an object of key to grade")
+	}
+	return nil
+}
+
+// ReadRetrievalQueries parses one query per line from JSONL. Queries without
+// an id are numbered from 1.
+func ReadRetrievalQueries(r io.Reader) ([]RetrievalQuery, error) {
+	var queries []RetrievalQuery
+	err := ScanJSONL(r, func(lineNo int, q RetrievalQuery) error {
+		if q.Query == "" {
+			return fmt.Errorf("missing query")
+		}
+		if len(q.Relevant) == 0 {
+			return fmt.Errorf("no relevant documents")
+		}
+		if q.ID == "" {
+			q.ID = fmt.Sprint(len(queries) + 1)
+		}
+		queries = append(queries, q)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return queries, nil
+}
+
+// RetrievalQueryResult holds the metrics of a single query at k.
+type RetrievalQueryResult struct {
+	ID             string   `json:"id"`
+	Query          string   `json:"query"`
+	Retrieved      []string `json:"retrieved"`
+	Hits           []string `json:"hits"`
+	Misses         []string `json:"misses"`
+	Recall         float64  `json:"recall"`
+	Precision      float64  `json:"precision"`
+	ReciprocalRank float64  `json:"reciprocal_rank"`
+	NDCG           float64  `json:"ndcg"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// RetrievalMetrics are metrics averaged over the queries that ran.
+type RetrievalMetrics struct {
+	K         int     `json:"k"`
+	Queries   int     `json:"queries"`
+	Failed    int     `json:"failed"`
+	Recall    float64 `json:"recall"`
+	Precision float64 `json:"precision"`
+	MRR       float64 `json:"mrr"`
+	NDCG      float64 `json:"ndcg"`
+}
+
+// RetrievalEvaluation is the result of running a query set against an index.
+type RetrievalEvaluation struct {
+	Index   string                 `json:"index"`
+	Metrics RetrievalMetrics       `json:"metrics"`
+	Queries []RetrievalQueryResult `json:"queries"`
+}
+
+// RetrievalEvalOptions controls EvaluateRetrieval.
+type RetrievalEvalOptions struct {
+	// K is the number of results retrieved per query, 10 if zero.
+	K int
+	// Concurrency is the number of queries run in parallel, 4 if zero.
+	Concurrency int
+	// OnResult, if set, is called as each query completes.
+	OnResult func(RetrievalQueryResult)
+}
+
+// EvaluateRetrieval runs each query against an index and scores the results
+// with ScoreRetrieval. Queries that fail are reported with an error and left
+// out of the averages.
+func (c *IndexesClient) EvaluateRetrieval(name string, queries []RetrievalQuery, opts RetrievalEvalOptions) (*RetrievalEvaluation, error) {
+	if opts.K <= 0 {
+		opts.K = 10
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+
+	// Fail fast on a missing index instead of once per query
+	if _, err := c.Get(context.Background(), name); err != nil {
+		return nil, err
+	}
+
+	results := make([]RetrievalQueryResult, len(queries))
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				q := queries[i]
+				retrieved, err := c.QueryWithOptions(name, q.Query, &QueryOptions{Filters: q.Filters, TopK: opts.K})
+				result := ScoreRetrieval(q, retrieved, opts.K)
+				if err != nil {
+					result = RetrievalQueryResult{ID: q.ID, Query: q.Query, Error: err.Error()}
+				}
+				results[i] = result
+
+				if opts.OnResult != nil {
+					mu.Lock()
+					opts.OnResult(result)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range queries {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	eval := &RetrievalEvaluation{
+		Index:   name,
+		Metrics: RetrievalMetrics{K: opts.K, Queries: len(queries)},
+		Queries: results,
+	}
+	ran := 0
+	for _, r := range results {
+		if r.Error != "" {
+			eval.Metrics.Failed++
+			continue
+		}
+		ran++
+		eval.Metrics.Recall += r.Recall
+		eval.Metrics.Precision += r.Precision
+		eval.Metrics.MRR += r.ReciprocalRank
+		eval.Metrics.NDCG += r.NDCG
+	}
+	if ran > 0 {
+		eval.Metrics.Recall /= float64(ran)
+		eval.Metrics.Precision /= float64(ran)
+		eval.Metrics.MRR /= float64(ran)
+		eval.Metrics.NDCG /= float64(ran)
+	}
+
+	return eval, nil
+}
+
+// ScoreRetrieval computes recall@k, precision@k, reciprocal rank and nDCG@k
+// for the results of a query.
+//
+// A result matches a relevant key when its key equals it, when its key is a
+// chunk of it ("guide.md#3" matches "guide.md"), or when its "source"
+// metadata equals it, so query sets keep working when documents are chunked
+// differently. Each relevant key counts once; further chunks of the same
+// document are not hits.
+func ScoreRetrieval(q RetrievalQuery, results []RetrievalResponse, k int) RetrievalQueryResult {
+	if len(results) > k {
+		results = results[:k]
+	}
+
+	result := RetrievalQueryResult{ID: q.ID, Query: q.Query, Retrieved: []string{}, Hits: []string{}, Misses: []string{}}
+	found := map[string]bool{}
+	var dcg float64
+
+	for i, r := range results {
+		result.Retrieved = append(result.Retrieved, r.Key)
+		key, ok := matchRelevant(q.Relevant, r)
+		if !ok || found[key] {
+			continue
+		}
+		found[key] = true
+		result.Hits = append(result.Hits, key)
+		dcg += q.Relevant[key] / math.Log2(float64(i+2))
+		if result.ReciprocalRank == 0 {
+			result.ReciprocalRank = 1 / float64(i+1)
+		}
+	}
+
+	grades := make([]float64, 0, len(q.Relevant))
+	for key, grade := range q.Relevant {
+		grades = append(grades, grade)
+		if !found[key] {
+			result.Misses = append(result.Misses, key)
+		}
+	}
+	sort.Strings(result.Misses)
+
+	// The ideal ranking puts the highest grades first
+	sort.Sort(sort.Reverse(sort.Float64Slice(grades)))
+	var idcg float64
+	for i, grade := range grades {
+		if i == k {
+			break
+		}
+		idcg += grade / math.Log2(float64(i+2))
+	}
+
+	if len(q.Relevant) > 0 {
+		result.Recall = float64(len(found)) / float64(len(q.Relevant))
+	}
+	if k > 0 {
+		result.Precision = float64(len(found)) / float64(k)
+	}
+	if idcg > 0 {
+		result.NDCG = dcg / idcg
+	}
+	return result
+}
+
+func matchRelevant(relevant map[string]float64, r RetrievalResponse) (string, bool) {
+	if _, ok := relevant[r.Key]; ok {
+		return r.Key, true
+	}
+	if i := strings.LastIndex(r.Key, "#"); i > 0 {
+		if _, ok := relevant[r.Key[:i]]; ok {
+			return r.Key[:i], true
+		}
+	}
+	if source, ok := r.Metadata["source"].(string); ok {
+		if _, ok := relevant[source]; ok {
+			return source, true
+		}
+	}
+	return "", false
+}
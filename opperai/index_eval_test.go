@@ -0,0 +1,178 @@
+package opperai
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScoreRetrieval(t *testing.T) {
+	results := []RetrievalResponse{
+		{Key: "other"},
+		{Key: "guide.md#2"},
+		{Key: "guide.md#5"},
+		{Key: "chunk-9", Metadata: map[string]interface{}{"source": "faq.md"}},
+	}
+
+	tests := []struct {
+		name      string
+		relevant  map[string]float64
+		k         int
+		hits      []string
+		misses    []string
+		recall    float64
+		precision float64
+		rr        float64
+		ndcg      float64
+	}{
+		{
+			name:      "chunk and source matches",
+			relevant:  map[string]float64{"guide.md": 1, "faq.md": 1},
+			k:         4,
+			hits:      []string{"guide.md", "faq.md"},
+			misses:    []string{},
+			recall:    1,
+			precision: 0.5,
+			rr:        0.5,
+			ndcg:      (1/math.Log2(3) + 1/math.Log2(5)) / (1 + 1/math.Log2(3)),
+		},
+		{
+			name:      "cut off at k",
+			relevant:  map[string]float64{"guide.md": 1, "faq.md": 1, "missing": 1},
+			k:         2,
+			hits:      []string{"guide.md"},
+			misses:    []string{"faq.md", "missing"},
+			recall:    1.0 / 3,
+			precision: 0.5,
+			rr:        0.5,
+			ndcg:      (1 / math.Log2(3)) / (1 + 1/math.Log2(3)),
+		},
+		{
+			name:      "graded relevance",
+			relevant:  map[string]float64{"other": 1, "faq.md": 3},
+			k:         4,
+			hits:      []string{"other", "faq.md"},
+			misses:    []string{},
+			recall:    1,
+			precision: 0.5,
+			rr:        1,
+			ndcg:      (1 + 3/math.Log2(5)) / (3 + 1/math.Log2(3)),
+		},
+		{
+			name:     "nothing found",
+			relevant: map[string]float64{"missing": 1},
+			k:        4,
+			hits:     []string{},
+			misses:   []string{"missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreRetrieval(RetrievalQuery{ID: "q", Relevant: tt.relevant}, results, tt.k)
+			if !reflect.DeepEqual(got.Hits, tt.hits) || !reflect.DeepEqual(got.Misses, tt.misses) {
+				t.Errorf("hits %v misses %v, want %v and %v", got.Hits, got.Misses, tt.hits, tt.misses)
+			}
+			for _, m := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"recall", got.Recall, tt.recall},
+				{"precision", got.Precision, tt.precision},
+				{"reciprocal rank", got.ReciprocalRank, tt.rr},
+				{"ndcg", got.NDCG, tt.ndcg},
+			} {
+				if math.Abs(m.got-m.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", m.name, m.got, m.want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadRetrievalQueries(t *testing.T) {
+	input := `{"query": "install", "relevant": ["a", "b"]}
+
+{"id": "pricing", "query": "cost", "expected": {"c": 2}, "filters": [{"field": "lang", "operation": "=", "value": "en"}]}`
+
+	queries, err := ReadRetrievalQueries(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadRetrievalQueries() error = %v", err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("got %d queries, want 2", len(queries))
+	}
+	if queries[0].ID != "1" || !reflect.DeepEqual(queries[0].Relevant, map[string]float64{"a": 1, "b": 1}) {
+		t.Errorf("unexpected first query %+v", queries[0])
+	}
+	if queries[1].ID != "pricing" || queries[1].Relevant["c"] != 2 || len(queries[1].Filters) != 1 {
+		t.Errorf("unexpected second query %+v", queries[1])
+	}
+
+	for _, bad := range []string{`{"relevant": ["a"]}`, `{"query": "x"}`, `{"query": "x", "relevant": "a"}`} {
+		if _, err := ReadRetrievalQueries(strings.NewReader(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestEvaluateRetrieval(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case indexesByName + "/docs":
+			json.NewEncoder(w).Encode(Index{Name: "docs"})
+		case "/v1/indexes/query/by-name/docs":
+			var body struct {
+				Q string `json:"q"`
+				K int    `json:"k"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.K != 3 {
+				t.Errorf("expected k=3, got %d", body.K)
+			}
+			if body.Q == "broken" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode([]RetrievalResponse{{Key: "a"}, {Key: "b"}, {Key: "c"}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	queries := []RetrievalQuery{
+		{ID: "1", Query: "first", Relevant: map[string]float64{"a": 1}},
+		{ID: "2", Query: "second", Relevant: map[string]float64{"c": 1, "z": 1}},
+		{ID: "3", Query: "broken", Relevant: map[string]float64{"a": 1}},
+	}
+
+	client := NewClient("test-key", server.URL)
+	done := 0
+	eval, err := client.Indexes.EvaluateRetrieval("docs", queries, RetrievalEvalOptions{
+		K:        3,
+		OnResult: func(RetrievalQueryResult) { done++ },
+	})
+	if err != nil {
+		t.Fatalf("EvaluateRetrieval() error = %v", err)
+	}
+	if done != 3 {
+		t.Errorf("OnResult called %d times, want 3", done)
+	}
+
+	m := eval.Metrics
+	if m.Queries != 3 || m.Failed != 1 {
+		t.Errorf("got %d queries and %d failed", m.Queries, m.Failed)
+	}
+	if math.Abs(m.Recall-0.75) > 1e-9 || math.Abs(m.MRR-(1+1.0/3)/2) > 1e-9 {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+	if eval.Queries[2].Error == "" {
+		t.Errorf("expected an error for the failed query")
+	}
+}
@@ -0,0 +1,140 @@
+package opperai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// IndexExport is a snapshot of an index: its documents and the list of files
+// uploaded to it. File contents cannot be downloaded from an index, so files
+// are recorded for reference only.
+type IndexExport struct {
+	Index      string
+	ExportedAt time.Time
+	Files      []File
+	Documents  []Document
+}
+
+// exportRecord is one line of an export. The first line describes the index,
+// followed by one line per file and per document.
+type exportRecord struct {
+	Type       string     `json:"type"`
+	Index      string     `json:"index,omitempty"`
+	ExportedAt *time.Time `json:"exported_at,omitempty"`
+	Files      *int       `json:"files,omitempty"`
+	Documents  *int       `json:"documents,omitempty"`
+	File       *File      `json:"file,omitempty"`
+	Document   *Document  `json:"document,omitempty"`
+}
+
+const (
+	exportTypeIndex    = "index"
+	exportTypeFile     = "file"
+	exportTypeDocument = "document"
+)
+
+// Export reads every document and the file list of an index. onProgress, if
+// set, is called after each document with the number read so far and the
+// total.
+func (c *IndexesClient) Export(name string, onProgress func(done, total int)) (*IndexExport, error) {
+	index, err := c.Get(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &IndexExport{
+		Index:      index.Name,
+		ExportedAt: time.Now().UTC(),
+		Files:      index.Files,
+	}
+
+	it := c.IterDocuments(name, &ListOptions{PageSize: 100})
+	for it.Next() {
+		export.Documents = append(export.Documents, it.Value())
+		if onProgress != nil {
+			onProgress(len(export.Documents), it.Total())
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("error reading documents: %w", err)
+	}
+
+	return export, nil
+}
+
+// WriteExport writes an export as JSONL.
+func WriteExport(w io.Writer, export *IndexExport) error {
+	enc := json.NewEncoder(w)
+
+	files, docs := len(export.Files), len(export.Documents)
+	header := exportRecord{
+		Type:       exportTypeIndex,
+		Index:      export.Index,
+		ExportedAt: &export.ExportedAt,
+		Files:      &files,
+		Documents:  &docs,
+	}
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+
+	for i := range export.Files {
+		if err := enc.Encode(exportRecord{Type: exportTypeFile, File: &export.Files[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range export.Documents {
+		if err := enc.Encode(exportRecord{Type: exportTypeDocument, Document: &export.Documents[i]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadExport parses an export written by WriteExport. Lines without a type
+// are read as plain documents, so document JSONL such as the output of
+// WriteDocumentsJSONL can be imported too.
+func ReadExport(r io.Reader) (*IndexExport, error) {
+	export := &IndexExport{}
+	err := ScanJSONL(r, func(lineNo int, line json.RawMessage) error {
+		var record exportRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("invalid record: %w", err)
+		}
+
+		switch record.Type {
+		case exportTypeIndex:
+			export.Index = record.Index
+			if record.ExportedAt != nil {
+				export.ExportedAt = *record.ExportedAt
+			}
+		case exportTypeFile:
+			if record.File == nil {
+				return fmt.Errorf("missing file")
+			}
+			export.Files = append(export.Files, *record.File)
+		case exportTypeDocument, "":
+			doc := record.Document
+			if doc == nil {
+				doc = &Document{}
+				if err := json.Unmarshal(line, doc); err != nil {
+					return fmt.Errorf("invalid document: %w", err)
+				}
+			}
+			if doc.Key == "" || doc.Content == "" {
+				return fmt.Errorf("document needs a key and content")
+			}
+			export.Documents = append(export.Documents, *doc)
+		default:
+			return fmt.Errorf("unknown record type %q", record.Type)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
@@ -0,0 +1,132 @@
+package opperai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestExportRoundTrip(t *testing.T) {
+	var docs []Document
+	for i := 0; i < 150; i++ {
+		docs = append(docs, Document{
+			Key:      fmt.Sprintf("doc-%d", i),
+			Content:  fmt.Sprintf("content %d", i),
+			Metadata: map[string]interface{}{"n": float64(i)},
+		})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case indexesByName + "/docs":
+			json.NewEncoder(w).Encode(Index{Name: "docs", Files: []File{{UUID: "f1", OriginalFilename: "guide.pdf"}}})
+		case indexesByName + "/docs/documents":
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			var response DocumentsResponse
+			response.Meta.TotalCount = len(docs)
+			response.Data = docs[offset:min(offset+limit, len(docs))]
+			json.NewEncoder(w).Encode(response)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	calls := 0
+	export, err := client.Indexes.Export("docs", func(done, total int) {
+		calls++
+		if total != len(docs) {
+			t.Errorf("progress total = %d, want %d", total, len(docs))
+		}
+	})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if calls != len(docs) {
+		t.Errorf("progress called %d times, want %d", calls, len(docs))
+	}
+
+	var buf bytes.Buffer
+	if err := WriteExport(&buf, export); err != nil {
+		t.Fatalf("WriteExport() error = %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 1+1+len(docs) {
+		t.Errorf("export has %d lines, want %d", lines, 2+len(docs))
+	}
+
+	read, err := ReadExport(&buf)
+	if err != nil {
+		t.Fatalf("ReadExport() error = %v", err)
+	}
+	if read.Index != "docs" || !read.ExportedAt.Equal(export.ExportedAt) {
+		t.Errorf("got index %q exported at %v", read.Index, read.ExportedAt)
+	}
+	if !reflect.DeepEqual(read.Documents, docs) {
+		t.Errorf("documents differ after round trip")
+	}
+	if len(read.Files) != 1 || read.Files[0].OriginalFilename != "guide.pdf" {
+		t.Errorf("unexpected files %+v", read.Files)
+	}
+}
+
+func TestReadExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantDocs int
+		wantErr  string
+	}{
+		{
+			name:     "plain documents",
+			input:    `{"key":"a","content":"x"}` + "\n\n" + `{"key":"b","content":"y","metadata":{"lang":"en"}}`,
+			wantDocs: 2,
+		},
+		{
+			name:     "document records",
+			input:    `{"type":"index","index":"docs"}` + "\n" + `{"type":"document","document":{"key":"a","content":"x"}}`,
+			wantDocs: 1,
+		},
+		{
+			name:    "missing key",
+			input:   `{"content":"x"}`,
+			wantErr: "line 1: document needs a key and content",
+		},
+		{
+			name:    "unknown type",
+			input:   `{"type":"index"}` + "\n" + `{"type":"chunk"}`,
+			wantErr: `line 2: unknown record type "chunk"`,
+		},
+		{
+			name:    "invalid JSON",
+			input:   `{"key":`,
+			wantErr: "line 1: invalid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export, err := ReadExport(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadExport() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadExport() error = %v", err)
+			}
+			if len(export.Documents) != tt.wantDocs {
+				t.Errorf("got %d documents, want %d", len(export.Documents), tt.wantDocs)
+			}
+		})
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 type IndexesClient struct {
@@ -78,7 +80,7 @@ func (c *IndexesClient) Delete(name string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("index not found: %s", name)
+		return fmt.Errorf("%w: %s", ErrIndexNotFound, name)
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
@@ -88,15 +90,15 @@ func (c *IndexesClient) Delete(name string) error {
 	return nil
 }
 
-func (c *IndexesClient) Get(name string) (*Index, error) {
-	resp, err := c.client.DoRequest(context.Background(), "GET", fmt.Sprintf("/v1/indexes/by-name/%s", name), nil)
+func (c *IndexesClient) Get(ctx context.Context, name string) (*Index, error) {
+	resp, err := c.client.DoRequest(ctx, "GET", fmt.Sprintf("/v1/indexes/by-name/%s", name), nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("index not found: %s", name)
+		return nil, fmt.Errorf("%w: %s", ErrIndexNotFound, name)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -111,10 +113,29 @@ func (c *IndexesClient) Get(name string) (*Index, error) {
 	return &index, nil
 }
 
+// QueryOptions controls an index query.
+type QueryOptions struct {
+	Filters []Filter
+	// TopK is the number of results to return. Zero uses the server default.
+	TopK int
+}
+
 func (c *IndexesClient) Query(name string, query string, filters []Filter) ([]RetrievalResponse, error) {
+	return c.QueryWithOptions(name, query, &QueryOptions{Filters: filters})
+}
+
+// QueryWithOptions queries an index with filters and a result limit.
+func (c *IndexesClient) QueryWithOptions(name string, query string, opts *QueryOptions) ([]RetrievalResponse, error) {
+	if opts == nil {
+		opts = &QueryOptions{}
+	}
+
 	body := map[string]interface{}{
 		"q":       query,
-		"filters": filters,
+		"filters": opts.Filters,
+	}
+	if opts.TopK > 0 {
+		body["k"] = opts.TopK
 	}
 
 	data, err := json.Marshal(body)
@@ -129,7 +150,7 @@ func (c *IndexesClient) Query(name string, query string, filters []Filter) ([]Re
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("index not found: %s", name)
+		return nil, fmt.Errorf("%w: %s", ErrIndexNotFound, name)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -157,7 +178,7 @@ func (c *IndexesClient) Add(name string, doc Document) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("index not found: %s", name)
+		return fmt.Errorf("%w: %s", ErrIndexNotFound, name)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -168,21 +189,32 @@ func (c *IndexesClient) Add(name string, doc Document) error {
 }
 
 func (c *IndexesClient) UploadFile(name string, filePath string) error {
+	_, err := c.UploadFileAs(context.Background(), name, filePath, filepath.Base(filePath))
+	return err
+}
+
+// UploadFileAs uploads a file under the given filename, which lets files
+// from different directories keep distinct names such as "guide/README.md".
+// It returns the registered file, which starts out pending until indexed.
+func (c *IndexesClient) UploadFileAs(ctx context.Context, name string, filePath string, filename string) (*File, error) {
 	// First get upload URL with filename as query parameter
-	filename := filepath.Base(filePath)
 	resp, err := c.client.DoRequest(
-		context.Background(),
+		ctx,
 		"GET",
-		fmt.Sprintf("/v1/indexes/upload_url/by-name/%s?filename=%s", name, filename),
+		fmt.Sprintf("/v1/indexes/upload_url/by-name/%s?filename=%s", name, url.QueryEscape(filename)),
 		nil,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("index not found: %s", name)
+		return nil, fmt.Errorf("%w: %s", ErrIndexNotFound, name)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Op: "get upload URL", StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
 	var uploadData struct {
@@ -191,19 +223,19 @@ func (c *IndexesClient) UploadFile(name string, filePath string) error {
 		UUID   string            `json:"uuid"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&uploadData); err != nil {
-		return fmt.Errorf("failed to decode upload URL response: %v", err)
+		return nil, fmt.Errorf("failed to decode upload URL response: %v", err)
 	}
 
 	// Read file
 	file, err := os.Open(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to open file: %v", err)
+		return nil, fmt.Errorf("failed to open file: %v", err)
 	}
 	defer file.Close()
 
 	// Upload file to URL
-	if err := c.client.uploadFile(uploadData.URL, uploadData.Fields, file); err != nil {
-		return fmt.Errorf("failed to upload file: %v", err)
+	if err := c.client.uploadFile(ctx, uploadData.URL, uploadData.Fields, file); err != nil {
+		return nil, err
 	}
 
 	// Register file
@@ -211,18 +243,115 @@ func (c *IndexesClient) UploadFile(name string, filePath string) error {
 		"uuid": uploadData.UUID,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	resp, err = c.client.DoRequest(context.Background(), "POST", fmt.Sprintf("/v1/indexes/register_file/by-name/%s", name), bytes.NewReader(registerData))
+	resp, err = c.client.DoRequest(ctx, "POST", fmt.Sprintf("/v1/indexes/register_file/by-name/%s", name), bytes.NewReader(registerData))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to register file with status %s", resp.Status)
+		return nil, &StatusError{Op: "register file", StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	uploaded := &File{
+		OriginalFilename: filename,
+		UUID:             uploadData.UUID,
+		IndexStatus:      "pending",
+	}
+	if info, err := os.Stat(filePath); err == nil {
+		uploaded.Size = info.Size()
+	}
+
+	// Prefer the server's view of the file when it returns one
+	var registered File
+	if err := json.NewDecoder(resp.Body).Decode(&registered); err == nil && registered.UUID != "" {
+		uploaded = &registered
+	}
+
+	return uploaded, nil
+}
+
+// DeleteFile removes an uploaded file and its content from an index.
+func (c *IndexesClient) DeleteFile(name string, fileUUID string) error {
+	resp, err := c.client.DoRequest(context.Background(), "DELETE", fmt.Sprintf("/v1/indexes/by-name/%s/files/%s", name, fileUUID), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("file not found in index %s: %s", name, fileUUID)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete file with status %s", resp.Status)
 	}
 
 	return nil
 }
+
+// WaitForIndexed polls the index until the given files have been indexed or
+// failed, calling onUpdate with their current state after every poll. With
+// no file UUIDs it waits for every file in the index. Files that do not
+// appear in the index yet are treated as pending. When ctx ends, including
+// during a poll, the last known state is returned with ctx.Err().
+func (c *IndexesClient) WaitForIndexed(ctx context.Context, name string, fileUUIDs []string, interval time.Duration, onUpdate func([]File)) ([]File, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// The last known state, returned if ctx ends during a poll
+	files := make([]File, len(fileUUIDs))
+	for i, uuid := range fileUUIDs {
+		files[i] = File{UUID: uuid, IndexStatus: FileStatusPending}
+	}
+
+	for {
+		index, err := c.Get(ctx, name)
+		if err != nil {
+			if ctx.Err() != nil {
+				return files, ctx.Err()
+			}
+			return nil, err
+		}
+
+		files = index.Files
+		if len(fileUUIDs) > 0 {
+			byUUID := make(map[string]File, len(index.Files))
+			for _, f := range index.Files {
+				byUUID[f.UUID] = f
+			}
+			files = make([]File, len(fileUUIDs))
+			for i, uuid := range fileUUIDs {
+				f, ok := byUUID[uuid]
+				if !ok {
+					f = File{UUID: uuid, IndexStatus: FileStatusPending}
+				}
+				files[i] = f
+			}
+		}
+
+		if onUpdate != nil {
+			onUpdate(files)
+		}
+
+		done := true
+		for _, f := range files {
+			if !f.Done() {
+				done = false
+				break
+			}
+		}
+		if done {
+			return files, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return files, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package opperai
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 const (
@@ -187,6 +193,9 @@ func TestDeleteIndex(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if tt.statusCode == http.StatusNotFound && !errors.Is(err, ErrIndexNotFound) {
+				t.Errorf("Delete() error = %v, want ErrIndexNotFound", err)
+			}
 		})
 	}
 }
@@ -266,3 +275,253 @@ func TestQueryIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryIndexWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		if body["k"] != float64(5) {
+			t.Errorf("expected k 5, got %v", body["k"])
+		}
+		filters, _ := body["filters"].([]interface{})
+		if len(filters) != 1 {
+			t.Errorf("expected 1 filter, got %v", body["filters"])
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		filter := filters[0].(map[string]interface{})
+		if filter["field"] != "lang" || filter["operation"] != "=" || filter["value"] != "go" {
+			t.Errorf("unexpected filter %v", filter)
+		}
+		json.NewEncoder(w).Encode([]RetrievalResponse{{Key: "a", Content: "result", Score: 0.5}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	results, err := client.Indexes.QueryWithOptions("test-index", "query", &QueryOptions{
+		Filters: []Filter{{Field: "lang", Operation: FilterEqual, Value: "go"}},
+		TopK:    5,
+	})
+	if err != nil {
+		t.Fatalf("QueryWithOptions() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Key != "a" {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
+
+func TestUploadFileAs(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(filePath, []byte("# Guide"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var uploaded, registered string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/indexes/upload_url/by-name/docs":
+			if got := r.URL.Query().Get("filename"); got != "guide/README.md" {
+				t.Errorf("expected filename guide/README.md, got %s", got)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"url":    server.URL + "/upload",
+				"fields": map[string]string{"key": "abc"},
+				"uuid":   "file-uuid",
+			})
+		case "/upload":
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("expected multipart file: %v", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			uploaded = string(data)
+			w.WriteHeader(http.StatusNoContent)
+		case "/v1/indexes/register_file/by-name/docs":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			registered = body["uuid"]
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	file, err := client.Indexes.UploadFileAs(context.Background(), "docs", filePath, "guide/README.md")
+	if err != nil {
+		t.Fatalf("UploadFileAs() error = %v", err)
+	}
+	if file.UUID != "file-uuid" || file.OriginalFilename != "guide/README.md" || file.Size != 7 {
+		t.Errorf("unexpected file %+v", file)
+	}
+	if uploaded != "# Guide" {
+		t.Errorf("expected uploaded content %q, got %q", "# Guide", uploaded)
+	}
+	if registered != "file-uuid" {
+		t.Errorf("expected registered uuid file-uuid, got %q", registered)
+	}
+}
+
+func TestUploadFileAsStatusError(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(filePath, []byte("# Guide"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	_, err := client.Indexes.UploadFileAs(context.Background(), "docs", filePath, "README.md")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected a 429 StatusError, got %v", err)
+	}
+	if err.Error() != "failed to get upload URL with status 429 Too Many Requests" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
+
+func TestDeleteIndexFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "successful delete", statusCode: http.StatusNoContent},
+		{name: "file not found", statusCode: http.StatusNotFound, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("expected DELETE request, got %s", r.Method)
+				}
+				expectedPath := indexesByName + "/docs/files/file-uuid"
+				if r.URL.Path != expectedPath {
+					t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+				}
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL)
+			err := client.Indexes.DeleteFile("docs", "file-uuid")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileDone(t *testing.T) {
+	tests := []struct {
+		status     string
+		wantDone   bool
+		wantFailed bool
+	}{
+		{status: ""},
+		{status: FileStatusPending},
+		{status: FileStatusProcessing},
+		{status: FileStatusCompleted, wantDone: true},
+		{status: FileStatusFailed, wantDone: true, wantFailed: true},
+		{status: "error", wantDone: true, wantFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			f := File{IndexStatus: tt.status}
+			if f.Done() != tt.wantDone || f.Failed() != tt.wantFailed {
+				t.Errorf("Done() = %v, Failed() = %v, want %v, %v", f.Done(), f.Failed(), tt.wantDone, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestWaitForIndexed(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		files := []File{
+			{UUID: "a", IndexStatus: FileStatusProcessing},
+			{UUID: "other", IndexStatus: FileStatusPending},
+		}
+		if polls >= 2 {
+			files[0].IndexStatus = FileStatusCompleted
+			files = append(files, File{UUID: "b", IndexStatus: FileStatusFailed, IndexStatusMessage: "unsupported format"})
+		}
+		json.NewEncoder(w).Encode(Index{Name: "docs", Files: files})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	updates := 0
+	files, err := client.Indexes.WaitForIndexed(context.Background(), "docs", []string{"a", "b"}, time.Millisecond, func([]File) {
+		updates++
+	})
+	if err != nil {
+		t.Fatalf("WaitForIndexed() error = %v", err)
+	}
+	if polls != 2 || updates != 2 {
+		t.Errorf("expected 2 polls and updates, got %d and %d", polls, updates)
+	}
+	if len(files) != 2 || files[0].IndexStatus != FileStatusCompleted || !files[1].Failed() {
+		t.Errorf("unexpected files %+v", files)
+	}
+}
+
+func TestWaitForIndexedTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Index{Name: "docs"})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	files, err := client.Indexes.WaitForIndexed(ctx, "docs", []string{"missing"}, 5*time.Millisecond, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if len(files) != 1 || files[0].IndexStatus != FileStatusPending {
+		t.Errorf("expected missing file to be pending, got %+v", files)
+	}
+}
+
+func TestWaitForIndexedHungPoll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	files, err := client.Indexes.WaitForIndexed(ctx, "docs", []string{"file-1"}, time.Millisecond, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("poll was not interrupted, waited %s", elapsed)
+	}
+	if len(files) != 1 || files[0].IndexStatus != FileStatusPending {
+		t.Errorf("expected the file to be pending, got %+v", files)
+	}
+}
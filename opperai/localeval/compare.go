@@ -0,0 +1,271 @@
+package localeval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+// CompareOptions configures Compare.
+type CompareOptions struct {
+	// Name is the function name calls are recorded under.
+	Name         string
+	Instructions string
+	// Concurrency is the number of calls in flight at once (default 4).
+	Concurrency int
+	// Timeout bounds each call. Zero means no timeout.
+	Timeout time.Duration
+	// JudgeModel, if set, ranks the outputs of each case with that model.
+	JudgeModel string
+	// Criteria tells the judge what makes an output better.
+	Criteria string
+	// OnOutput is called after each call. Calls are serialized, so the
+	// callback does not need to be safe for concurrent use.
+	OnOutput func(done, total int, output ModelOutput)
+}
+
+// ModelOutput is the result of one model on one case.
+type ModelOutput struct {
+	Model     string  `json:"model"`
+	Output    string  `json:"output,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+	Tokens    int     `json:"tokens"`
+	Cost      float64 `json:"cost"`
+	// Rank is the judge's ranking, 1 being best, or 0 when not ranked.
+	Rank int `json:"rank,omitempty"`
+}
+
+// CaseComparison holds the outputs of every model for one case, in the
+// order the models were given.
+type CaseComparison struct {
+	Case         Case          `json:"case"`
+	Outputs      []ModelOutput `json:"outputs"`
+	JudgeComment string        `json:"judge_comment,omitempty"`
+	JudgeError   string        `json:"judge_error,omitempty"`
+}
+
+// ModelSummary aggregates the results of one model over all cases.
+type ModelSummary struct {
+	Model     string                    `json:"model"`
+	Succeeded int                       `json:"succeeded"`
+	Failed    int                       `json:"failed"`
+	Latency   opperai.StatisticsSummary `json:"latency_ms"`
+	Tokens    int                       `json:"tokens"`
+	Cost      float64                   `json:"cost"`
+	// Wins counts the cases the judge ranked this model first.
+	Wins    int     `json:"wins"`
+	AvgRank float64 `json:"avg_rank,omitempty"`
+}
+
+// Comparison is the result of Compare.
+type Comparison struct {
+	Models  []string         `json:"models"`
+	Judge   string           `json:"judge,omitempty"`
+	Cases   []CaseComparison `json:"cases"`
+	Summary []ModelSummary   `json:"summary"`
+}
+
+// Compare makes the same call with every model for every case and, with a
+// judge model, ranks the outputs of each case. Failed calls are recorded in
+// the output rather than returned as errors.
+func Compare(ctx context.Context, call *opperai.CallClient, cases []Case, models []string, opts *CompareOptions) (*Comparison, error) {
+	o := CompareOptions{Concurrency: defaultConcurrency}
+	if opts != nil {
+		o = *opts
+		if o.Concurrency <= 0 {
+			o.Concurrency = defaultConcurrency
+		}
+	}
+	if len(models) < 2 {
+		return nil, fmt.Errorf("at least two models are required")
+	}
+	if o.Name == "" {
+		o.Name = "localeval/compare"
+	}
+
+	comparison := &Comparison{Models: models, Judge: o.JudgeModel, Cases: make([]CaseComparison, len(cases))}
+	for i, c := range cases {
+		comparison.Cases[i] = CaseComparison{Case: c, Outputs: make([]ModelOutput, len(models))}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	done, total := 0, len(cases)*len(models)
+	sem := make(chan struct{}, o.Concurrency)
+
+	for i, c := range cases {
+		for j, model := range models {
+			if ctx.Err() != nil {
+				break
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i, j int, c Case, model string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				output := runModel(ctx, call, c, model, o)
+
+				mu.Lock()
+				comparison.Cases[i].Outputs[j] = output
+				done++
+				if o.OnOutput != nil {
+					o.OnOutput(done, total, output)
+				}
+				mu.Unlock()
+			}(i, j, c, model)
+		}
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if o.JudgeModel != "" {
+		for i := range comparison.Cases {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(cc *CaseComparison) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if err := judgeCase(ctx, call, cc, o); err != nil {
+					cc.JudgeError = err.Error()
+				}
+			}(&comparison.Cases[i])
+		}
+		wg.Wait()
+	}
+
+	comparison.Summary = summarizeComparison(comparison)
+	return comparison, nil
+}
+
+func runModel(ctx context.Context, call *opperai.CallClient, c Case, model string, o CompareOptions) ModelOutput {
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	output := ModelOutput{Model: model}
+	start := time.Now()
+	resp, err := call.Call(ctx, o.Name, o.Instructions, c.Input, model, false, nil)
+	output.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		output.Error = err.Error()
+		return output
+	}
+
+	output.Output = resp.Message
+	output.Tokens = resp.Usage.TotalTokens
+	output.Cost = resp.Cost.Total
+	return output
+}
+
+const rankInstructions = `You are comparing outputs of different AI models for the same task.
+Rank the outputs from best to worst according to the instructions, the criteria and, if given, the expected answer.
+Respond with only a JSON object of the form {"ranking": ["<label>", ...], "comment": "<short reason>"} listing every label once.`
+
+// judgeCase asks the judge model to rank the successful outputs of a case.
+// Outputs are labelled with letters so the judge does not see model names.
+func judgeCase(ctx context.Context, call *opperai.CallClient, cc *CaseComparison, o CompareOptions) error {
+	labels := map[string]int{}
+	var outputs []map[string]string
+	for i, output := range cc.Outputs {
+		if output.Error != "" {
+			continue
+		}
+		label := string(rune('A' + len(outputs)))
+		labels[label] = i
+		outputs = append(outputs, map[string]string{"label": label, "output": output.Output})
+	}
+	// Nothing to compare, so leave the case unranked rather than count a win
+	if len(outputs) < 2 {
+		return nil
+	}
+
+	input, err := json.Marshal(map[string]interface{}{
+		"instructions": o.Instructions,
+		"criteria":     o.Criteria,
+		"input":        cc.Case.Input,
+		"expected":     cc.Case.Expected,
+		"outputs":      outputs,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := call.Call(ctx, "localeval/rank", rankInstructions, string(input), o.JudgeModel, false, nil)
+	if err != nil {
+		return err
+	}
+
+	var verdict struct {
+		Ranking []string `json:"ranking"`
+		Comment string   `json:"comment"`
+	}
+	if err := json.Unmarshal([]byte(opperai.StripCodeFence(resp.Message)), &verdict); err != nil {
+		return fmt.Errorf("judge returned an invalid ranking: %s", resp.Message)
+	}
+
+	ranks := map[int]int{}
+	for _, label := range verdict.Ranking {
+		i, ok := labels[strings.TrimSpace(label)]
+		if !ok {
+			return fmt.Errorf("judge returned an unknown label %q", label)
+		}
+		if _, dup := ranks[i]; !dup {
+			ranks[i] = len(ranks) + 1
+		}
+	}
+	if len(ranks) != len(outputs) {
+		return fmt.Errorf("judge ranked %d of %d outputs", len(ranks), len(outputs))
+	}
+
+	for i, rank := range ranks {
+		cc.Outputs[i].Rank = rank
+	}
+	cc.JudgeComment = verdict.Comment
+	return nil
+}
+
+func summarizeComparison(comparison *Comparison) []ModelSummary {
+	summaries := make([]ModelSummary, len(comparison.Models))
+	for j, model := range comparison.Models {
+		s := ModelSummary{Model: model}
+		var latencies []float64
+		rankSum, ranked := 0, 0
+		for _, cc := range comparison.Cases {
+			output := cc.Outputs[j]
+			if output.Error != "" {
+				s.Failed++
+				continue
+			}
+			s.Succeeded++
+			latencies = append(latencies, output.LatencyMs)
+			s.Tokens += output.Tokens
+			s.Cost += output.Cost
+			if output.Rank > 0 {
+				rankSum += output.Rank
+				ranked++
+				if output.Rank == 1 {
+					s.Wins++
+				}
+			}
+		}
+		s.Latency = opperai.Summarize(latencies)
+		if ranked > 0 {
+			s.AvgRank = float64(rankSum) / float64(ranked)
+		}
+		summaries[j] = s
+	}
+	return summaries
+}
@@ -0,0 +1,139 @@
+package localeval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name         string
+		ranking      string
+		wantRanks    [][]int
+		wantWins     []int
+		wantJudgeErr bool
+	}{
+		{
+			name:      "ranked",
+			ranking:   `{"ranking": ["B", "A"], "comment": "B is shorter"}`,
+			wantRanks: [][]int{{2, 1, 0}, {2, 1, 0}},
+			wantWins:  []int{0, 2, 0},
+		},
+		{
+			name:         "invalid ranking",
+			ranking:      `{"ranking": ["A", "Z"]}`,
+			wantRanks:    [][]int{{0, 0, 0}, {0, 0, 0}},
+			wantWins:     []int{0, 0, 0},
+			wantJudgeErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&payload)
+				switch payload["model"] {
+				case "judge":
+					input, _ := payload["input"].(string)
+					if strings.Contains(input, `"model"`) || !strings.Contains(input, `"label":"B"`) {
+						t.Errorf("unexpected judge input %s", input)
+					}
+					json.NewEncoder(w).Encode(map[string]string{"message": tt.ranking})
+				case "broken":
+					w.WriteHeader(http.StatusInternalServerError)
+					w.Write([]byte("unavailable"))
+				default:
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"message": payload["model"].(string) + ": " + payload["input"].(string),
+						"usage":   map[string]int{"total_tokens": 10},
+						"cost":    map[string]float64{"total": 0.5},
+					})
+				}
+			}))
+			defer server.Close()
+
+			client := opperai.NewClient("test-key", server.URL)
+			cases := []Case{{ID: "1", Input: "one"}, {ID: "2", Input: "two"}}
+			calls := 0
+			comparison, err := Compare(context.Background(), client.Call, cases, []string{"model-a", "model-b", "broken"}, &CompareOptions{
+				Instructions: "answer",
+				JudgeModel:   "judge",
+				OnOutput:     func(done, total int, _ ModelOutput) { calls = done },
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if calls != 6 {
+				t.Errorf("OnOutput reported %d calls, want 6", calls)
+			}
+
+			for i, cc := range comparison.Cases {
+				if cc.Outputs[0].Output != "model-a: "+cases[i].Input || cc.Outputs[2].Error == "" {
+					t.Errorf("case %d: unexpected outputs %+v", i, cc.Outputs)
+				}
+				if (cc.JudgeError != "") != tt.wantJudgeErr {
+					t.Errorf("case %d: judge error = %q", i, cc.JudgeError)
+				}
+				for j, output := range cc.Outputs {
+					if output.Rank != tt.wantRanks[i][j] {
+						t.Errorf("case %d model %d: rank = %d, want %d", i, j, output.Rank, tt.wantRanks[i][j])
+					}
+				}
+			}
+
+			for j, s := range comparison.Summary {
+				if s.Wins != tt.wantWins[j] {
+					t.Errorf("%s: wins = %d, want %d", s.Model, s.Wins, tt.wantWins[j])
+				}
+			}
+			if s := comparison.Summary[0]; s.Succeeded != 2 || s.Tokens != 20 || s.Cost != 1 {
+				t.Errorf("unexpected summary %+v", s)
+			}
+			if s := comparison.Summary[2]; s.Failed != 2 || s.Succeeded != 0 {
+				t.Errorf("unexpected summary %+v", s)
+			}
+		})
+	}
+}
+
+func TestCompareRequiresTwoModels(t *testing.T) {
+	client := opperai.NewClient("test-key", "http://127.0.0.1:0")
+	if _, err := Compare(context.Background(), client.Call, []Case{{Input: "x"}}, []string{"a"}, nil); err == nil {
+		t.Error("expected error with a single model")
+	}
+}
+
+func TestCompareSingleOutputUnranked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		switch payload["model"] {
+		case "judge":
+			t.Error("judge called with a single successful output")
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			json.NewEncoder(w).Encode(map[string]string{"message": "ok"})
+		}
+	}))
+	defer server.Close()
+
+	client := opperai.NewClient("test-key", server.URL)
+	comparison, err := Compare(context.Background(), client.Call, []Case{{Input: "x"}}, []string{"model-a", "broken"}, &CompareOptions{JudgeModel: "judge"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rank := comparison.Cases[0].Outputs[0].Rank; rank != 0 {
+		t.Errorf("rank = %d, want 0", rank)
+	}
+	if s := comparison.Summary[0]; s.Wins != 0 || s.AvgRank != 0 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+}
@@ -0,0 +1,274 @@
+// Package localeval runs evaluations against local fixtures instead of an
+// uploaded dataset.
+//
+// Cases are read from JSONL, sent through a Target (a stored function or an
+// ad-hoc call) with bounded concurrency and scored by one or more Scorers.
+// The result is an opperai.Evaluation, so it can be printed, exported and
+// compared exactly like evaluations run by the API:
+//
+//	cases, err := localeval.LoadCases("testdata/summaries.jsonl")
+//	target := localeval.CallTarget(client.Call, "summarize", "Summarize the text", "")
+//	eval, err := localeval.Run(ctx, cases, target, &localeval.Options{
+//		Scorers:     []localeval.Scorer{localeval.ExactMatch(true), localeval.Similarity()},
+//		Concurrency: 8,
+//	})
+package localeval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+const defaultConcurrency = 4
+
+// Case is a single input with its expected output.
+type Case struct {
+	ID       string `json:"id"`
+	Input    string `json:"input"`
+	Expected string `json:"expected"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// Target produces an output for a case input.
+type Target func(ctx context.Context, input string) (string, error)
+
+// CallTarget returns a Target that makes an ad-hoc call with the given name,
+// instructions and model. An empty model uses the server default.
+func CallTarget(call *opperai.CallClient, name, instructions, model string) Target {
+	return func(ctx context.Context, input string) (string, error) {
+		resp, err := call.Call(ctx, name, instructions, input, model, false, nil)
+		if err != nil {
+			return "", err
+		}
+		return resp.Message, nil
+	}
+}
+
+// FunctionTarget returns a Target that calls a stored function with its
+// current configuration, including its input and output schemas and
+// few-shot settings. A non-empty model overrides the function's model.
+// Functions with an input schema need JSON case inputs, and outputs of
+// functions with an output schema are scored as compact JSON.
+func FunctionTarget(ctx context.Context, client *opperai.Client, functionPath, model string) (Target, error) {
+	function, err := client.Functions.GetByPath(ctx, functionPath)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, input string) (string, error) {
+		resp, err := client.Call.CallFunction(ctx, function, input, model)
+		if err != nil {
+			return "", err
+		}
+		return resp.Message, nil
+	}, nil
+}
+
+// Options configures Run.
+type Options struct {
+	// Scorers are applied to every output; each one becomes a dimension.
+	Scorers []Scorer
+	// Concurrency is the number of cases run in parallel (default 4).
+	Concurrency int
+	// Timeout bounds each target call. Zero means no timeout.
+	Timeout time.Duration
+	// OnRecord is called after each case has been scored. Calls are
+	// serialized, so the callback does not need to be safe for concurrent use.
+	OnRecord func(done, total int, record opperai.EvaluationRecord)
+}
+
+// Run sends every case through target, scores the outputs and returns an
+// Evaluation with one record per case, in input order. Cases whose target
+// call fails are marked failed and score zero on every dimension.
+func Run(ctx context.Context, cases []Case, target Target, opts *Options) (*opperai.Evaluation, error) {
+	o := Options{Concurrency: defaultConcurrency}
+	if opts != nil {
+		o = *opts
+		if o.Concurrency <= 0 {
+			o.Concurrency = defaultConcurrency
+		}
+	}
+	if len(o.Scorers) == 0 {
+		return nil, fmt.Errorf("at least one scorer is required")
+	}
+
+	dimensions := make([]string, len(o.Scorers))
+	seen := make(map[string]bool, len(o.Scorers))
+	for i, scorer := range o.Scorers {
+		name := scorer.Name()
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate scorer name: %s", name)
+		}
+		seen[name] = true
+		dimensions[i] = name
+	}
+
+	start := time.Now().UTC()
+	records := make([]opperai.EvaluationRecord, len(cases))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	done := 0
+	sem := make(chan struct{}, o.Concurrency)
+
+	for i, c := range cases {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, c Case) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			record := runCase(ctx, c, i, target, o)
+
+			mu.Lock()
+			records[i] = record
+			done++
+			if o.OnRecord != nil {
+				o.OnRecord(done, len(cases), record)
+			}
+			mu.Unlock()
+		}(i, c)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	evaluation := &opperai.Evaluation{
+		EvaluationUUID:    fmt.Sprintf("local-%d", start.UnixNano()),
+		Records:           records,
+		Status:            opperai.EvaluationStatus{State: opperai.EvaluationStateCompleted},
+		Dimensions:        dimensions,
+		SummaryStatistics: make(map[string]opperai.StatisticsSummary, len(dimensions)),
+		CreatedAt:         start.Format(time.RFC3339),
+		UpdatedAt:         time.Now().UTC().Format(time.RFC3339),
+	}
+
+	failed := 0
+	for _, record := range records {
+		if record.Status.Failed() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		evaluation.Status.Details = fmt.Sprintf("%d of %d cases failed", failed, len(records))
+	}
+
+	for _, dim := range dimensions {
+		values := make([]float64, 0, len(records))
+		for _, record := range records {
+			if metric, ok := record.Metrics[dim]; ok {
+				values = append(values, metric.Value)
+			}
+		}
+		evaluation.SummaryStatistics[dim] = opperai.Summarize(values)
+	}
+
+	return evaluation, nil
+}
+
+func runCase(ctx context.Context, c Case, index int, target Target, o Options) opperai.EvaluationRecord {
+	id := c.ID
+	if id == "" {
+		id = fmt.Sprintf("case-%d", index+1)
+	}
+
+	record := opperai.EvaluationRecord{
+		EvaluationRecordUUID: id,
+		DatasetEntryUUID:     id,
+		Input:                c.Input,
+		Expected:             c.Expected,
+		Metrics:              make(map[string]opperai.EvaluationMetric, len(o.Scorers)),
+	}
+
+	callCtx := ctx
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	output, err := target(callCtx, c.Input)
+	if err != nil {
+		record.Status = opperai.EvaluationStatus{State: opperai.EvaluationStateFailed, Details: err.Error()}
+		for _, scorer := range o.Scorers {
+			record.Metrics[scorer.Name()] = opperai.EvaluationMetric{
+				Dimension: scorer.Name(),
+				Comment:   "target failed",
+			}
+		}
+		return record
+	}
+
+	record.Output = output
+	record.Status = opperai.EvaluationStatus{State: opperai.EvaluationStateCompleted}
+	for _, scorer := range o.Scorers {
+		score, err := scorer.Score(ctx, c, output)
+		if err != nil {
+			score = Score{Comment: fmt.Sprintf("scorer error: %v", err)}
+		}
+		record.Metrics[scorer.Name()] = opperai.EvaluationMetric{
+			Dimension: scorer.Name(),
+			Value:     score.Value,
+			Comment:   score.Comment,
+		}
+	}
+
+	return record
+}
+
+// LoadCases reads cases from a JSONL file.
+func LoadCases(path string) ([]Case, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadCases(f)
+}
+
+// ReadCases parses one case per line. Each line is an object with "input"
+// and optional "id", "expected" (or "output") and "comment" fields;
+// non-string inputs and expectations are kept as compact JSON.
+func ReadCases(r io.Reader) ([]Case, error) {
+	var cases []Case
+	ids := map[string]int{}
+	err := opperai.ScanJSONL(r, func(lineNo int, raw map[string]json.RawMessage) error {
+		c := Case{
+			ID:       opperai.RawToString(raw["id"]),
+			Input:    opperai.RawToString(raw["input"]),
+			Expected: opperai.RawToString(raw["expected"]),
+			Comment:  opperai.RawToString(raw["comment"]),
+		}
+		if c.Expected == "" {
+			c.Expected = opperai.RawToString(raw["output"])
+		}
+		if c.Input == "" {
+			return fmt.Errorf("missing input")
+		}
+		if c.ID != "" {
+			if prev, ok := ids[c.ID]; ok {
+				return fmt.Errorf("duplicate id %q (first seen on line %d)", c.ID, prev)
+			}
+			ids[c.ID] = lineNo
+		}
+		cases = append(cases, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
@@ -0,0 +1,193 @@
+package localeval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+func TestReadCases(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		want        []Case
+		expectError bool
+	}{
+		{
+			name:  "strings and json values",
+			input: "{\"id\":\"a\",\"input\":\"hi\",\"expected\":\"hello\"}\n\n{\"input\":{\"q\":1},\"output\":[1,2]}\n",
+			want: []Case{
+				{ID: "a", Input: "hi", Expected: "hello"},
+				{Input: `{"q":1}`, Expected: "[1,2]"},
+			},
+		},
+		{
+			name:        "missing input",
+			input:       `{"expected":"x"}`,
+			expectError: true,
+		},
+		{
+			name:        "duplicate id",
+			input:       "{\"id\":\"a\",\"input\":\"x\"}\n{\"id\":\"a\",\"input\":\"y\"}\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadCases(strings.NewReader(tt.input))
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d cases, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("case %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	cases := []Case{
+		{ID: "one", Input: "1", Expected: "one"},
+		{ID: "two", Input: "2", Expected: "two"},
+		{Input: "3", Expected: "three"},
+		{ID: "fail", Input: "boom", Expected: "x"},
+	}
+	outputs := map[string]string{"1": "one", "2": "TWO", "3": "tree"}
+
+	var calls int32
+	target := func(_ context.Context, input string) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		if out, ok := outputs[input]; ok {
+			return out, nil
+		}
+		return "", errors.New("target failed")
+	}
+
+	var progress int
+	evaluation, err := Run(context.Background(), cases, target, &Options{
+		Scorers:     []Scorer{ExactMatch(false), Similarity()},
+		Concurrency: 2,
+		OnRecord:    func(done, total int, _ opperai.EvaluationRecord) { progress = done },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 4 || progress != 4 {
+		t.Errorf("calls = %d, progress = %d, want 4 and 4", calls, progress)
+	}
+	if len(evaluation.Records) != 4 {
+		t.Fatalf("got %d records, want 4", len(evaluation.Records))
+	}
+	if got := evaluation.Records[2].DatasetEntryUUID; got != "case-3" {
+		t.Errorf("generated id = %q, want case-3", got)
+	}
+	if !evaluation.Records[3].Status.Failed() {
+		t.Errorf("expected failed record, got %+v", evaluation.Records[3].Status)
+	}
+	if evaluation.Status.Details != "1 of 4 cases failed" {
+		t.Errorf("details = %q", evaluation.Status.Details)
+	}
+
+	exact := evaluation.SummaryStatistics["exact_match"]
+	if exact.Count != 4 || exact.Sum != 1 {
+		t.Errorf("exact_match summary = %+v, want count 4 and sum 1", exact)
+	}
+	if got := evaluation.Records[2].Metrics["similarity"].Value; got != 0.8 {
+		t.Errorf("similarity(tree, three) = %v, want 0.8", got)
+	}
+}
+
+func TestRunRequiresUniqueScorers(t *testing.T) {
+	target := func(context.Context, string) (string, error) { return "", nil }
+
+	if _, err := Run(context.Background(), nil, target, nil); err == nil {
+		t.Error("expected error without scorers")
+	}
+
+	opts := &Options{Scorers: []Scorer{Similarity(), Similarity()}}
+	if _, err := Run(context.Background(), nil, target, opts); err == nil {
+		t.Error("expected error for duplicate scorer names")
+	}
+
+	opts = &Options{Scorers: []Scorer{Similarity(), Named("similarity_2", Similarity())}}
+	if _, err := Run(context.Background(), nil, target, opts); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCallTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["name"] != "greet" || payload["instructions"] != "say hi" {
+			t.Errorf("unexpected payload: %v", payload)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"message": "hi " + payload["input"].(string)})
+	}))
+	defer server.Close()
+
+	client := opperai.NewClient("test-key", server.URL)
+	target := CallTarget(client.Call, "greet", "say hi", "")
+
+	got, err := target(context.Background(), "bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "hi bob" {
+		t.Errorf("got %q, want %q", got, "hi bob")
+	}
+}
+
+func TestFunctionTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/functions/by_path/extract" {
+			json.NewEncoder(w).Encode(opperai.FunctionDescription{
+				Path:         "extract",
+				Instructions: "extract",
+				Model:        "openai/gpt-4o",
+				OutputSchema: map[string]interface{}{"type": "object"},
+			})
+			return
+		}
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["output_schema"] == nil {
+			t.Errorf("expected the function's output schema, got %v", payload)
+		}
+		w.Write([]byte(`{"json_payload": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := opperai.NewClient("test-key", server.URL)
+	target, err := FunctionTarget(context.Background(), client, "extract", "")
+	if err != nil {
+		t.Fatalf("FunctionTarget() error = %v", err)
+	}
+	got, err := target(context.Background(), "text")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != `{"ok":true}` {
+		t.Errorf("got %q, want %q", got, `{"ok":true}`)
+	}
+}
@@ -0,0 +1,217 @@
+package localeval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+// Score is the result of scoring one output. Values range from 0 to 1.
+type Score struct {
+	Value   float64
+	Comment string
+}
+
+// Scorer grades the output produced for a case. The scorer name is used as
+// the evaluation dimension, so it must be unique within a run.
+type Scorer interface {
+	Name() string
+	Score(ctx context.Context, c Case, output string) (Score, error)
+}
+
+type scorerFunc struct {
+	name string
+	fn   func(ctx context.Context, c Case, output string) (Score, error)
+}
+
+func (s scorerFunc) Name() string { return s.name }
+
+func (s scorerFunc) Score(ctx context.Context, c Case, output string) (Score, error) {
+	return s.fn(ctx, c, output)
+}
+
+// NewScorer wraps a function as a Scorer.
+func NewScorer(name string, fn func(ctx context.Context, c Case, output string) (Score, error)) Scorer {
+	return scorerFunc{name: name, fn: fn}
+}
+
+// Named returns s reporting under a different dimension name, which allows
+// the same kind of scorer to be used more than once in a run.
+func Named(name string, s Scorer) Scorer {
+	return scorerFunc{name: name, fn: s.Score}
+}
+
+// ExactMatch scores 1 when the output equals the expected value after
+// trimming surrounding whitespace, and 0 otherwise.
+func ExactMatch(ignoreCase bool) Scorer {
+	return NewScorer("exact_match", func(_ context.Context, c Case, output string) (Score, error) {
+		got, want := strings.TrimSpace(output), strings.TrimSpace(c.Expected)
+		if got == want || (ignoreCase && strings.EqualFold(got, want)) {
+			return Score{Value: 1}, nil
+		}
+		return Score{Value: 0}, nil
+	})
+}
+
+// JSONFields parses the output and the expected value as JSON and scores the
+// fraction of the given fields that are equal. Fields use dotted paths such
+// as "address.city". With no fields the whole documents are compared.
+func JSONFields(fields ...string) Scorer {
+	return NewScorer("json_fields", func(_ context.Context, c Case, output string) (Score, error) {
+		var want interface{}
+		if err := json.Unmarshal([]byte(c.Expected), &want); err != nil {
+			return Score{}, fmt.Errorf("expected value is not valid JSON: %w", err)
+		}
+
+		var got interface{}
+		if err := json.Unmarshal([]byte(opperai.StripCodeFence(output)), &got); err != nil {
+			return Score{Value: 0, Comment: "output is not valid JSON"}, nil
+		}
+
+		if len(fields) == 0 {
+			if reflect.DeepEqual(got, want) {
+				return Score{Value: 1}, nil
+			}
+			return Score{Value: 0, Comment: "documents differ"}, nil
+		}
+
+		var mismatched []string
+		for _, field := range fields {
+			g, gok := lookupField(got, field)
+			w, wok := lookupField(want, field)
+			if gok != wok || !reflect.DeepEqual(g, w) {
+				mismatched = append(mismatched, field)
+			}
+		}
+
+		score := Score{Value: float64(len(fields)-len(mismatched)) / float64(len(fields))}
+		if len(mismatched) > 0 {
+			score.Comment = "mismatched: " + strings.Join(mismatched, ", ")
+		}
+		return score, nil
+	})
+}
+
+// Regex scores 1 when the output matches pattern. An empty pattern uses each
+// case's expected value as the pattern instead.
+func Regex(pattern string) (Scorer, error) {
+	var re *regexp.Regexp
+	if pattern != "" {
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+
+	return NewScorer("regex", func(_ context.Context, c Case, output string) (Score, error) {
+		r := re
+		if r == nil {
+			var err error
+			if r, err = regexp.Compile(c.Expected); err != nil {
+				return Score{}, fmt.Errorf("invalid expected pattern: %w", err)
+			}
+		}
+		if r.MatchString(output) {
+			return Score{Value: 1}, nil
+		}
+		return Score{Value: 0, Comment: fmt.Sprintf("no match for %s", r)}, nil
+	}), nil
+}
+
+// Similarity scores the normalized Levenshtein similarity between the output
+// and the expected value, from 0 (nothing in common) to 1 (identical).
+func Similarity() Scorer {
+	return NewScorer("similarity", func(_ context.Context, c Case, output string) (Score, error) {
+		return Score{Value: similarity(strings.TrimSpace(output), strings.TrimSpace(c.Expected))}, nil
+	})
+}
+
+const judgeInstructions = `You are grading the output of an AI function.
+Compare the output with the expected answer according to the criteria.
+Respond with only a JSON object of the form {"score": <number from 0 to 1>, "comment": "<short reason>"}.`
+
+// LLMJudge asks a model to grade each output against the expected value and
+// the given criteria. An empty model uses the server default.
+func LLMJudge(call *opperai.CallClient, criteria, model string) Scorer {
+	return NewScorer("judge", func(ctx context.Context, c Case, output string) (Score, error) {
+		input, err := json.Marshal(map[string]string{
+			"criteria": criteria,
+			"input":    c.Input,
+			"expected": c.Expected,
+			"output":   output,
+		})
+		if err != nil {
+			return Score{}, err
+		}
+
+		resp, err := call.Call(ctx, "localeval/judge", judgeInstructions, string(input), model, false, nil)
+		if err != nil {
+			return Score{}, err
+		}
+
+		var verdict struct {
+			Score   *float64 `json:"score"`
+			Comment string   `json:"comment"`
+		}
+		if err := json.Unmarshal([]byte(opperai.StripCodeFence(resp.Message)), &verdict); err != nil || verdict.Score == nil {
+			return Score{}, fmt.Errorf("judge returned an invalid verdict: %s", resp.Message)
+		}
+
+		return Score{
+			Value:   math.Max(0, math.Min(1, *verdict.Score)),
+			Comment: verdict.Comment,
+		}, nil
+	})
+}
+
+// lookupField resolves a dotted path in a decoded JSON document
+func lookupField(doc interface{}, path string) (interface{}, bool) {
+	current := doc
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// similarity returns 1 minus the Levenshtein distance divided by the length
+// of the longer string, measured in runes
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	longest := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	return 1 - float64(levenshtein([]rune(a), []rune(b)))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,117 @@
+package localeval
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opper-ai/oppercli/opperai"
+)
+
+func TestScorers(t *testing.T) {
+	mustRegex := func(pattern string) Scorer {
+		s, err := Regex(pattern)
+		if err != nil {
+			t.Fatalf("Regex(%q): %v", pattern, err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name        string
+		scorer      Scorer
+		expected    string
+		output      string
+		want        float64
+		expectError bool
+	}{
+		{name: "exact match", scorer: ExactMatch(false), expected: "Paris", output: " Paris\n", want: 1},
+		{name: "exact match case sensitive", scorer: ExactMatch(false), expected: "Paris", output: "paris", want: 0},
+		{name: "exact match ignore case", scorer: ExactMatch(true), expected: "Paris", output: "paris", want: 1},
+		{name: "json fields partial", scorer: JSONFields("name", "address.city"), expected: `{"name":"Ann","address":{"city":"Oslo"}}`, output: "```json\n{\"name\":\"Ann\",\"address\":{\"city\":\"Bergen\"}}\n```", want: 0.5},
+		{name: "json whole document", scorer: JSONFields(), expected: `{"a":[1,2]}`, output: `{"a": [1, 2]}`, want: 1},
+		{name: "json invalid output", scorer: JSONFields("a"), expected: `{"a":1}`, output: "not json", want: 0},
+		{name: "json invalid expected", scorer: JSONFields("a"), expected: "nope", output: `{"a":1}`, expectError: true},
+		{name: "regex fixed pattern", scorer: mustRegex(`^\d{4}-\d{2}-\d{2}$`), output: "2024-01-31", want: 1},
+		{name: "regex from expected", scorer: mustRegex(""), expected: `(?i)hello`, output: "HELLO there", want: 1},
+		{name: "similarity identical", scorer: Similarity(), expected: "kitten", output: "kitten", want: 1},
+		{name: "similarity distance", scorer: Similarity(), expected: "kitten", output: "sitting", want: 1 - 3.0/7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, err := tt.scorer.Score(context.Background(), Case{Expected: tt.expected}, tt.output)
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(score.Value-tt.want) > 1e-9 {
+				t.Errorf("score = %v, want %v", score.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestLLMJudge(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		want        Score
+		expectError bool
+	}{
+		{
+			name:    "valid verdict",
+			message: `{"score": 0.75, "comment": "mostly right"}`,
+			want:    Score{Value: 0.75, Comment: "mostly right"},
+		},
+		{
+			name:    "clamped verdict",
+			message: "```json\n{\"score\": 3}\n```",
+			want:    Score{Value: 1},
+		},
+		{
+			name:        "invalid verdict",
+			message:     "looks good",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&payload)
+				if payload["model"] != "judge-model" {
+					t.Errorf("model = %v, want judge-model", payload["model"])
+				}
+				json.NewEncoder(w).Encode(map[string]string{"message": tt.message})
+			}))
+			defer server.Close()
+
+			client := opperai.NewClient("test-key", server.URL)
+			judge := LLMJudge(client.Call, "is it correct", "judge-model")
+
+			got, err := judge.Score(context.Background(), Case{Input: "q", Expected: "a"}, "a")
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
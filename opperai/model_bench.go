@@ -0,0 +1,234 @@
+package opperai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// BenchOptions controls a model benchmark.
+type BenchOptions struct {
+	Model string
+	// Name is the function name calls are recorded under.
+	Name         string
+	Instructions string
+	// Prompts are used in turn as call inputs.
+	Prompts     []string
+	Requests    int
+	Concurrency int
+	// Warmup requests are sent before measuring and not counted.
+	Warmup int
+	// Timeout limits each request; zero means no limit.
+	Timeout time.Duration
+	// OnSample, if set, is called after each measured request.
+	OnSample func(BenchSample)
+}
+
+// BenchSample is the measurement of a single streamed call.
+type BenchSample struct {
+	TTFT    time.Duration
+	Latency time.Duration
+	// Tokens is the number of output tokens, estimated from the length of
+	// the output since streamed responses do not report usage.
+	Tokens float64
+	Err    error
+}
+
+// LatencyStats summarizes durations in milliseconds.
+type LatencyStats struct {
+	Min  float64 `json:"min_ms"`
+	Mean float64 `json:"mean_ms"`
+	P50  float64 `json:"p50_ms"`
+	P95  float64 `json:"p95_ms"`
+	P99  float64 `json:"p99_ms"`
+	Max  float64 `json:"max_ms"`
+}
+
+// BenchResult summarizes the samples of a benchmark. Latencies and
+// throughput only cover successful requests.
+type BenchResult struct {
+	Model       string       `json:"model"`
+	Requests    int          `json:"requests"`
+	Succeeded   int          `json:"succeeded"`
+	Failed      int          `json:"failed"`
+	SuccessRate float64      `json:"success_rate"`
+	TTFT        LatencyStats `json:"ttft"`
+	Latency     LatencyStats `json:"latency"`
+	// TokensPerSecond is the mean generation speed after the first token.
+	TokensPerSecond float64        `json:"tokens_per_second"`
+	Errors          map[string]int `json:"errors,omitempty"`
+}
+
+const (
+	DefaultBenchName         = "opper/cli/model-bench"
+	DefaultBenchInstructions = "Respond to the input."
+	// charsPerToken is the usual ratio for English text
+	charsPerToken = 4.0
+)
+
+// Bench sends streamed calls to a model and measures time to first token,
+// total latency and output speed.
+func (c *CallClient) Bench(ctx context.Context, opts BenchOptions) (*BenchResult, error) {
+	if len(opts.Prompts) == 0 {
+		return nil, fmt.Errorf("at least one prompt is required")
+	}
+	if opts.Requests <= 0 {
+		opts.Requests = 10
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.Name == "" {
+		opts.Name = DefaultBenchName
+	}
+	if opts.Instructions == "" {
+		opts.Instructions = DefaultBenchInstructions
+	}
+
+	for i := 0; i < opts.Warmup; i++ {
+		if s := c.benchOnce(ctx, opts, opts.Prompts[i%len(opts.Prompts)]); s.Err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	samples := make([]BenchSample, opts.Requests)
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				samples[i] = c.benchOnce(ctx, opts, opts.Prompts[i%len(opts.Prompts)])
+				if opts.OnSample != nil {
+					mu.Lock()
+					opts.OnSample(samples[i])
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range samples {
+		if ctx.Err() != nil {
+			samples[i] = BenchSample{Err: ctx.Err()}
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := SummarizeBench(samples)
+	result.Model = opts.Model
+	return result, nil
+}
+
+func (c *CallClient) benchOnce(ctx context.Context, opts BenchOptions, prompt string) BenchSample {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	resp, err := c.Call(ctx, opts.Name, opts.Instructions, prompt, opts.Model, true, nil)
+	if err != nil {
+		return BenchSample{Err: err}
+	}
+
+	var sample BenchSample
+	chars := 0
+	for delta := range resp.Stream {
+		if chars == 0 {
+			sample.TTFT = time.Since(start)
+		}
+		chars += utf8.RuneCountInString(delta)
+	}
+	sample.Latency = time.Since(start)
+	sample.Tokens = float64(chars) / charsPerToken
+
+	switch {
+	case opts.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		sample.Err = fmt.Errorf("timed out after %s", opts.Timeout)
+	case ctx.Err() != nil:
+		sample.Err = ctx.Err()
+	case resp.Err() != nil:
+		sample.Err = resp.Err()
+	case chars == 0:
+		sample.Err = fmt.Errorf("empty response")
+	}
+	return sample
+}
+
+// SummarizeBench computes success rate, latency percentiles and throughput
+// from benchmark samples.
+func SummarizeBench(samples []BenchSample) *BenchResult {
+	result := &BenchResult{Requests: len(samples)}
+	var ttft, latency []float64
+	var speed float64
+
+	for _, s := range samples {
+		if s.Err != nil {
+			result.Failed++
+			if result.Errors == nil {
+				result.Errors = map[string]int{}
+			}
+			result.Errors[s.Err.Error()]++
+			continue
+		}
+		result.Succeeded++
+		ttft = append(ttft, durationMs(s.TTFT))
+		latency = append(latency, durationMs(s.Latency))
+
+		generation := s.Latency - s.TTFT
+		if generation <= 0 {
+			generation = s.Latency
+		}
+		if generation > 0 {
+			speed += s.Tokens / generation.Seconds()
+		}
+	}
+
+	if result.Requests > 0 {
+		result.SuccessRate = float64(result.Succeeded) / float64(result.Requests)
+	}
+	if result.Succeeded > 0 {
+		result.TTFT = latencyStats(ttft)
+		result.Latency = latencyStats(latency)
+		result.TokensPerSecond = speed / float64(result.Succeeded)
+	}
+	return result
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func latencyStats(values []float64) LatencyStats {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	summary := Summarize(sorted)
+	return LatencyStats{
+		Min:  summary.Min,
+		Mean: summary.Avg,
+		P50:  percentile(sorted, 50),
+		P95:  percentile(sorted, 95),
+		P99:  percentile(sorted, 99),
+		Max:  summary.Max,
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.999999) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
@@ -0,0 +1,186 @@
+package opperai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBench(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["model"] != "my-model" || body["stream"] != true || body["name"] != DefaultBenchName {
+			t.Errorf("unexpected call body %v", body)
+		}
+		// Every third call fails
+		if atomic.AddInt32(&calls, 1)%3 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("overloaded"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 2; i++ {
+			fmt.Fprintf(w, "data: {\"delta\": \"abcdefgh\"}\n\n")
+			w.(http.Flusher).Flush()
+			time.Sleep(5 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	var samples int32
+	result, err := client.Call.Bench(context.Background(), BenchOptions{
+		Model:       "my-model",
+		Prompts:     []string{"hello"},
+		Requests:    6,
+		Concurrency: 2,
+		OnSample:    func(BenchSample) { atomic.AddInt32(&samples, 1) },
+	})
+	if err != nil {
+		t.Fatalf("Bench() error = %v", err)
+	}
+
+	if result.Model != "my-model" || result.Requests != 6 || result.Succeeded != 4 || result.Failed != 2 {
+		t.Errorf("unexpected counts %+v", result)
+	}
+	if samples != 6 {
+		t.Errorf("OnSample called %d times, want 6", samples)
+	}
+	if result.Errors["API error: overloaded"] != 2 {
+		t.Errorf("unexpected errors %v", result.Errors)
+	}
+	if result.TTFT.P50 <= 0 || result.Latency.P50 < result.TTFT.P50 || result.Latency.Min < 5 {
+		t.Errorf("unexpected latencies ttft=%+v latency=%+v", result.TTFT, result.Latency)
+	}
+	if result.TokensPerSecond <= 0 {
+		t.Errorf("TokensPerSecond = %v, want > 0", result.TokensPerSecond)
+	}
+}
+
+func TestBenchTruncatedStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Promise more than is sent so the client sees the stream cut off
+		w.Header().Set("Content-Length", "1000")
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: {\"delta\": \"abcdefgh\"}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	result, err := client.Call.Bench(context.Background(), BenchOptions{
+		Model:    "my-model",
+		Prompts:  []string{"hello"},
+		Requests: 2,
+	})
+	if err != nil {
+		t.Fatalf("Bench() error = %v", err)
+	}
+	if result.Succeeded != 0 || result.Failed != 2 {
+		t.Errorf("truncated streams should fail, got %+v", result)
+	}
+}
+
+func TestBenchCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	client := NewClient("test-key", server.URL)
+	result, err := client.Call.Bench(ctx, BenchOptions{
+		Model:    "my-model",
+		Prompts:  []string{"hello"},
+		Requests: 1,
+		Timeout:  time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Bench() error = %v", err)
+	}
+	for msg := range result.Errors {
+		if strings.Contains(msg, "timed out") {
+			t.Errorf("cancellation reported as timeout: %q", msg)
+		}
+	}
+	if result.Failed != 1 {
+		t.Errorf("Failed = %d, want 1", result.Failed)
+	}
+}
+
+func TestBenchRequiresPrompt(t *testing.T) {
+	client := NewClient("test-key", "http://127.0.0.1:0")
+	if _, err := client.Call.Bench(context.Background(), BenchOptions{Model: "m"}); err == nil {
+		t.Error("expected error without prompts")
+	}
+}
+
+func TestSummarizeBench(t *testing.T) {
+	var samples []BenchSample
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, BenchSample{
+			TTFT:    time.Duration(i) * time.Millisecond,
+			Latency: time.Duration(i)*time.Millisecond + time.Second,
+			Tokens:  50,
+		})
+	}
+	samples = append(samples, BenchSample{Err: errors.New("boom")})
+
+	result := SummarizeBench(samples)
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"success rate", result.SuccessRate, 100.0 / 101},
+		{"ttft p50", result.TTFT.P50, 50},
+		{"ttft p95", result.TTFT.P95, 95},
+		{"ttft p99", result.TTFT.P99, 99},
+		{"ttft max", result.TTFT.Max, 100},
+		{"latency min", result.Latency.Min, 1001},
+		{"tokens per second", result.TokensPerSecond, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := tt.got - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+	if result.Errors["boom"] != 1 {
+		t.Errorf("unexpected errors %v", result.Errors)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{nil, 50, 0},
+		{[]float64{7}, 99, 7},
+		{[]float64{1, 2, 3, 4}, 50, 2},
+		{[]float64{1, 2, 3, 4}, 95, 4},
+		{[]float64{1, 2, 3, 4}, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.values, tt.p); got != tt.want {
+			t.Errorf("percentile(%v, %v) = %v, want %v", tt.values, tt.p, got, tt.want)
+		}
+	}
+}
@@ -74,8 +74,26 @@ func (c *ModelsClient) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
+// Update changes the given model. Empty name, identifier and API key are
+// left unchanged, so keys that cannot be read back are not cleared. A nil
+// Extra is left unchanged; any other Extra, including an empty one, replaces
+// the current one.
 func (c *ModelsClient) Update(ctx context.Context, name string, model CustomLanguageModel) error {
-	data, err := json.Marshal(model)
+	update := map[string]interface{}{}
+	if model.Name != "" {
+		update["name"] = model.Name
+	}
+	if model.Identifier != "" {
+		update["identifier"] = model.Identifier
+	}
+	if model.APIKey != "" {
+		update["api_key"] = model.APIKey
+	}
+	if model.Extra != nil {
+		update["extra"] = model.Extra
+	}
+
+	data, err := json.Marshal(update)
 	if err != nil {
 		return err
 	}
@@ -86,6 +104,10 @@ func (c *ModelsClient) Update(ctx context.Context, name string, model CustomLang
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("model not found: %s", name)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("failed to update model with status %s", resp.Status)
 	}
@@ -0,0 +1,105 @@
+package opperai
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Actions in a model plan
+const (
+	ModelActionCreate    = "create"
+	ModelActionUpdate    = "update"
+	ModelActionDelete    = "delete"
+	ModelActionUnchanged = "unchanged"
+)
+
+// ModelChange is a planned change to a custom model. Fields lists what an
+// update changes, such as "identifier" or "extra.api_version".
+type ModelChange struct {
+	Action string
+	Model  CustomLanguageModel
+	Fields []string
+}
+
+// PlanModelChanges compares the current custom models with the desired ones
+// and returns the changes that make them match, sorted by model name. API
+// keys cannot be read back, so they are not compared. Models that are not
+// desired are only deleted with prune.
+func PlanModelChanges(current, desired []CustomLanguageModel, prune bool) []ModelChange {
+	existing := make(map[string]CustomLanguageModel, len(current))
+	for _, m := range current {
+		existing[m.Name] = m
+	}
+
+	var changes []ModelChange
+	wanted := make(map[string]bool, len(desired))
+	for _, m := range desired {
+		wanted[m.Name] = true
+		cur, ok := existing[m.Name]
+		if !ok {
+			changes = append(changes, ModelChange{Action: ModelActionCreate, Model: m})
+			continue
+		}
+
+		fields := diffModels(cur, m)
+		action := ModelActionUnchanged
+		if len(fields) > 0 {
+			action = ModelActionUpdate
+		}
+		changes = append(changes, ModelChange{Action: action, Model: m, Fields: fields})
+	}
+
+	if prune {
+		for _, m := range current {
+			if !wanted[m.Name] {
+				changes = append(changes, ModelChange{Action: ModelActionDelete, Model: m})
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Model.Name < changes[j].Model.Name })
+	return changes
+}
+
+func diffModels(current, desired CustomLanguageModel) []string {
+	var fields []string
+	if current.Identifier != desired.Identifier {
+		fields = append(fields, "identifier")
+	}
+
+	cur, want := normalizeJSON(current.Extra), normalizeJSON(desired.Extra)
+	keys := map[string]bool{}
+	for k := range cur {
+		keys[k] = true
+	}
+	for k := range want {
+		keys[k] = true
+	}
+	var extra []string
+	for k := range keys {
+		if !reflect.DeepEqual(cur[k], want[k]) {
+			extra = append(extra, "extra."+k)
+		}
+	}
+	sort.Strings(extra)
+
+	return append(fields, extra...)
+}
+
+// normalizeJSON round-trips a map through JSON so values decoded from other
+// formats, such as YAML integers, compare equal to API responses
+func normalizeJSON(m map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	if len(m) == 0 {
+		return out
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return m
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return m
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package opperai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateModelPartial(t *testing.T) {
+	tests := []struct {
+		name  string
+		model CustomLanguageModel
+		want  map[string]interface{}
+	}{
+		{
+			name:  "only set fields",
+			model: CustomLanguageModel{Identifier: "azure/new"},
+			want:  map[string]interface{}{"identifier": "azure/new"},
+		},
+		{
+			name:  "empty extra clears it",
+			model: CustomLanguageModel{Extra: map[string]interface{}{}},
+			want:  map[string]interface{}{"extra": map[string]interface{}{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPatch || r.URL.Path != modelsByName+"/my-model" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				var body map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&body)
+				if !reflect.DeepEqual(body, tt.want) {
+					t.Errorf("got body %v, want %v", body, tt.want)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL)
+			if err := client.Models.Update(context.Background(), "my-model", tt.model); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPlanModelChanges(t *testing.T) {
+	current := []CustomLanguageModel{
+		{Name: "same", Identifier: "azure/a", Extra: map[string]interface{}{"api_version": "2024-06-01", "max_tokens": float64(100)}},
+		{Name: "changed", Identifier: "azure/b", Extra: map[string]interface{}{"api_base": "https://old"}},
+		{Name: "stale", Identifier: "azure/c"},
+	}
+	desired := []CustomLanguageModel{
+		{Name: "same", Identifier: "azure/a", Extra: map[string]interface{}{"api_version": "2024-06-01", "max_tokens": 100}},
+		{Name: "changed", Identifier: "azure/b2", Extra: map[string]interface{}{"api_base": "https://new", "api_version": "v2"}},
+		{Name: "added", Identifier: "bedrock/x"},
+	}
+
+	type change struct {
+		action, name string
+		fields       []string
+	}
+	summarize := func(changes []ModelChange) []change {
+		var out []change
+		for _, c := range changes {
+			out = append(out, change{c.Action, c.Model.Name, c.Fields})
+		}
+		return out
+	}
+
+	got := summarize(PlanModelChanges(current, desired, false))
+	want := []change{
+		{ModelActionCreate, "added", nil},
+		{ModelActionUpdate, "changed", []string{"identifier", "extra.api_base", "extra.api_version"}},
+		{ModelActionUnchanged, "same", nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("without prune got %+v, want %+v", got, want)
+	}
+
+	got = summarize(PlanModelChanges(current, desired, true))
+	want = append(want, change{ModelActionDelete, "stale", nil})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("with prune got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+package opperai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BuiltinModelFilter selects models from the built-in catalog. Empty fields
+// match every model. Strings are compared case-insensitively.
+type BuiltinModelFilter struct {
+	// Query matches a substring of the model name
+	Query string
+	// Providers and Locations match any of the given values exactly
+	Providers []string
+	Locations []string
+	// Capabilities must all be supported, either as a capability such as
+	// "tools" or as an input or output modality such as "image"
+	Capabilities []string
+	// MaxInputCost and MaxOutputCost are limits in USD per token. Models
+	// that do not report a cost never match a limit.
+	MaxInputCost  float64
+	MaxOutputCost float64
+	// MinContextWindow is the smallest acceptable context window in tokens
+	MinContextWindow  int
+	ExcludeDeprecated bool
+}
+
+// Builtin model sort keys
+const (
+	BuiltinSortName          = "name"
+	BuiltinSortProvider      = "provider"
+	BuiltinSortLocation      = "location"
+	BuiltinSortContextWindow = "context"
+	BuiltinSortInputCost     = "input-cost"
+	BuiltinSortOutputCost    = "output-cost"
+)
+
+// BuiltinSortKeys lists the keys accepted by SortBuiltinModels
+var BuiltinSortKeys = []string{
+	BuiltinSortName,
+	BuiltinSortProvider,
+	BuiltinSortLocation,
+	BuiltinSortContextWindow,
+	BuiltinSortInputCost,
+	BuiltinSortOutputCost,
+}
+
+// HasCapability reports whether the model lists capability or supports it
+// as an input or output modality.
+func (m BuiltinLanguageModel) HasCapability(capability string) bool {
+	for _, list := range [][]string{m.Capabilities, m.InputModalities, m.OutputModalities} {
+		for _, c := range list {
+			if strings.EqualFold(c, capability) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Matches reports whether the model passes every condition of the filter.
+func (f BuiltinModelFilter) Matches(m BuiltinLanguageModel) bool {
+	if f.Query != "" && !strings.Contains(strings.ToLower(m.Name), strings.ToLower(f.Query)) {
+		return false
+	}
+	if len(f.Providers) > 0 && !containsFold(f.Providers, m.HostingProvider) {
+		return false
+	}
+	if len(f.Locations) > 0 && !containsFold(f.Locations, m.Location) {
+		return false
+	}
+	for _, c := range f.Capabilities {
+		if !m.HasCapability(c) {
+			return false
+		}
+	}
+	if f.MaxInputCost > 0 && (m.InputCostPerToken <= 0 || m.InputCostPerToken > f.MaxInputCost) {
+		return false
+	}
+	if f.MaxOutputCost > 0 && (m.OutputCostPerToken <= 0 || m.OutputCostPerToken > f.MaxOutputCost) {
+		return false
+	}
+	if f.MinContextWindow > 0 && m.ContextWindow < f.MinContextWindow {
+		return false
+	}
+	if f.ExcludeDeprecated && m.Deprecated {
+		return false
+	}
+	return true
+}
+
+// FilterBuiltinModels returns the models matching the filter, in order.
+func FilterBuiltinModels(models []BuiltinLanguageModel, filter BuiltinModelFilter) []BuiltinLanguageModel {
+	var matched []BuiltinLanguageModel
+	for _, m := range models {
+		if filter.Matches(m) {
+			matched = append(matched, m)
+		}
+	}
+	return matched
+}
+
+// SortBuiltinModels sorts models in place by one of BuiltinSortKeys, ties
+// broken by name. Models without a reported cost or context window sort
+// last in either direction.
+func SortBuiltinModels(models []BuiltinLanguageModel, key string, reverse bool) error {
+	var value func(m BuiltinLanguageModel) float64
+	var text func(m BuiltinLanguageModel) string
+
+	switch key {
+	case BuiltinSortName, "":
+		text = func(m BuiltinLanguageModel) string { return m.Name }
+	case BuiltinSortProvider:
+		text = func(m BuiltinLanguageModel) string { return m.HostingProvider }
+	case BuiltinSortLocation:
+		text = func(m BuiltinLanguageModel) string { return m.Location }
+	case BuiltinSortContextWindow:
+		value = func(m BuiltinLanguageModel) float64 { return float64(m.ContextWindow) }
+	case BuiltinSortInputCost:
+		value = func(m BuiltinLanguageModel) float64 { return m.InputCostPerToken }
+	case BuiltinSortOutputCost:
+		value = func(m BuiltinLanguageModel) float64 { return m.OutputCostPerToken }
+	default:
+		return fmt.Errorf("unknown sort key: %s (must be one of %s)", key, strings.Join(BuiltinSortKeys, ", "))
+	}
+
+	sort.SliceStable(models, func(i, j int) bool {
+		a, b := models[i], models[j]
+		if value != nil {
+			va, vb := value(a), value(b)
+			if (va > 0) != (vb > 0) {
+				return va > 0
+			}
+			if va != vb {
+				return (va < vb) != reverse
+			}
+		} else {
+			ta, tb := strings.ToLower(text(a)), strings.ToLower(text(b))
+			if ta != tb {
+				return (ta < tb) != reverse
+			}
+		}
+		return a.Name < b.Name
+	})
+	return nil
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package opperai
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testCatalog = []BuiltinLanguageModel{
+	{Name: "openai/gpt-4o", HostingProvider: "OpenAI", Location: "US", ContextWindow: 128000, InputCostPerToken: 0.0000025, OutputCostPerToken: 0.00001, InputModalities: []string{"text", "image"}, Capabilities: []string{"tools", "json"}},
+	{Name: "azure/gpt-4o-eu", HostingProvider: "Azure", Location: "EU", ContextWindow: 128000, InputCostPerToken: 0.000005, OutputCostPerToken: 0.000015, InputModalities: []string{"text", "image"}, Capabilities: []string{"tools"}},
+	{Name: "mistral/mistral-small-eu", HostingProvider: "Mistral", Location: "eu", ContextWindow: 32000, InputCostPerToken: 0.0000002, OutputCostPerToken: 0.0000006, Capabilities: []string{"tools"}},
+	{Name: "mistral/mistral-tiny-eu", HostingProvider: "Mistral", Location: "EU", Deprecated: true},
+}
+
+func catalogNames(models []BuiltinLanguageModel) []string {
+	var names []string
+	for _, m := range models {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+func TestFilterBuiltinModels(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter BuiltinModelFilter
+		want   []string
+	}{
+		{"no filter", BuiltinModelFilter{}, []string{"openai/gpt-4o", "azure/gpt-4o-eu", "mistral/mistral-small-eu", "mistral/mistral-tiny-eu"}},
+		{"query", BuiltinModelFilter{Query: "GPT"}, []string{"openai/gpt-4o", "azure/gpt-4o-eu"}},
+		{"location ignores case", BuiltinModelFilter{Locations: []string{"EU"}}, []string{"azure/gpt-4o-eu", "mistral/mistral-small-eu", "mistral/mistral-tiny-eu"}},
+		{"providers", BuiltinModelFilter{Providers: []string{"azure", "openai"}}, []string{"openai/gpt-4o", "azure/gpt-4o-eu"}},
+		{"capability and modality", BuiltinModelFilter{Capabilities: []string{"tools", "image"}}, []string{"openai/gpt-4o", "azure/gpt-4o-eu"}},
+		{"max input cost excludes unknown", BuiltinModelFilter{MaxInputCost: 0.000003}, []string{"openai/gpt-4o", "mistral/mistral-small-eu"}},
+		{"max output cost", BuiltinModelFilter{MaxOutputCost: 0.000001}, []string{"mistral/mistral-small-eu"}},
+		{"min context", BuiltinModelFilter{MinContextWindow: 100000}, []string{"openai/gpt-4o", "azure/gpt-4o-eu"}},
+		{"exclude deprecated", BuiltinModelFilter{Locations: []string{"eu"}, ExcludeDeprecated: true}, []string{"azure/gpt-4o-eu", "mistral/mistral-small-eu"}},
+		{"no match", BuiltinModelFilter{Locations: []string{"APAC"}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := catalogNames(FilterBuiltinModels(testCatalog, tt.filter))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortBuiltinModels(t *testing.T) {
+	tests := []struct {
+		key     string
+		reverse bool
+		want    []string
+		wantErr bool
+	}{
+		{key: "name", want: []string{"azure/gpt-4o-eu", "mistral/mistral-small-eu", "mistral/mistral-tiny-eu", "openai/gpt-4o"}},
+		{key: "provider", reverse: true, want: []string{"openai/gpt-4o", "mistral/mistral-small-eu", "mistral/mistral-tiny-eu", "azure/gpt-4o-eu"}},
+		{key: "input-cost", want: []string{"mistral/mistral-small-eu", "openai/gpt-4o", "azure/gpt-4o-eu", "mistral/mistral-tiny-eu"}},
+		{key: "input-cost", reverse: true, want: []string{"azure/gpt-4o-eu", "openai/gpt-4o", "mistral/mistral-small-eu", "mistral/mistral-tiny-eu"}},
+		{key: "context", reverse: true, want: []string{"azure/gpt-4o-eu", "openai/gpt-4o", "mistral/mistral-small-eu", "mistral/mistral-tiny-eu"}},
+		{key: "popularity", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			models := append([]BuiltinLanguageModel(nil), testCatalog...)
+			err := SortBuiltinModels(models, tt.key, tt.reverse)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := catalogNames(models); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 )
 
@@ -399,6 +400,45 @@ func TestListBuiltinModels(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "all catalog fields",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`[{
+					"name": "mistral/mistral-large-eu",
+					"hosting_provider": "Mistral",
+					"location": "EU",
+					"context_window": 128000,
+					"max_output_tokens": 4096,
+					"input_cost_per_token": 0.000002,
+					"output_cost_per_token": 0.000006,
+					"input_modalities": ["text"],
+					"output_modalities": ["text"],
+					"capabilities": ["tools", "json"],
+					"deprecated": true,
+					"deprecation_date": "2025-01-01",
+					"replaced_by": "mistral/mistral-large-2-eu",
+					"release_date": "2024-02-26"
+				}]`))
+			},
+			want: []BuiltinLanguageModel{
+				{
+					Name:               "mistral/mistral-large-eu",
+					HostingProvider:    "Mistral",
+					Location:           "EU",
+					ContextWindow:      128000,
+					MaxOutputTokens:    4096,
+					InputCostPerToken:  0.000002,
+					OutputCostPerToken: 0.000006,
+					InputModalities:    []string{"text"},
+					OutputModalities:   []string{"text"},
+					Capabilities:       []string{"tools", "json"},
+					Deprecated:         true,
+					DeprecationDate:    "2025-01-01",
+					ReplacedBy:         "mistral/mistral-large-2-eu",
+					Extra:              map[string]interface{}{"release_date": "2024-02-26"},
+				},
+			},
+		},
 		{
 			name: "server error",
 			handler: func(w http.ResponseWriter, r *http.Request) {
@@ -434,7 +474,7 @@ func TestListBuiltinModels(t *testing.T) {
 			}
 
 			for i := range got {
-				if got[i] != tt.want[i] {
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
 					t.Errorf("model %d: got %+v, want %+v", i, got[i], tt.want[i])
 				}
 			}
@@ -24,6 +24,7 @@ type Client struct {
 	Indexes   *IndexesClient
 	Models    *ModelsClient
 	Functions *FunctionsClient
+	Datasets  *DatasetsClient
 	Call      *CallClient
 	Traces    *TracesClient
 	Usage     *UsageClient
@@ -46,6 +47,7 @@ func NewClient(apiKey string, baseURL ...string) *Client {
 	client.Indexes = newIndexesClient(client)
 	client.Models = newModelsClient(client)
 	client.Functions = newFunctionsClient(client)
+	client.Datasets = newDatasetsClient(client)
 	client.Call = newCallClient(client)
 	client.Traces = newTracesClient(client)
 	client.Usage = newUsageClient(client)
@@ -119,7 +121,7 @@ func (c *Client) Chat(ctx context.Context, functionPath string, data ChatPayload
 }
 
 // uploadFile is a helper function for file uploads
-func (c *Client) uploadFile(url string, fields map[string]string, file *os.File) error {
+func (c *Client) uploadFile(ctx context.Context, url string, fields map[string]string, file *os.File) error {
 	// Create a pipe to write the multipart form data
 	pr, pw := io.Pipe()
 	writer := multipart.NewWriter(pw)
@@ -157,7 +159,7 @@ func (c *Client) uploadFile(url string, fields map[string]string, file *os.File)
 	}()
 
 	// Create the HTTP request
-	req, err := http.NewRequest("POST", url, pr)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, pr)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
@@ -168,14 +170,14 @@ func (c *Client) uploadFile(url string, fields map[string]string, file *os.File)
 	// Send the request
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to upload file: %v", err)
+		return fmt.Errorf("failed to upload file: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check the response
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(body))
+		return &StatusError{Op: "upload file", StatusCode: resp.StatusCode, Status: resp.Status, Body: string(body)}
 	}
 
 	return nil
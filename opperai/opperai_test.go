@@ -47,6 +47,9 @@ func TestNewClient(t *testing.T) {
 			if client.Functions == nil {
 				t.Error("NewClient() Functions is nil")
 			}
+			if client.Datasets == nil {
+				t.Error("NewClient() Datasets is nil")
+			}
 		})
 	}
 }
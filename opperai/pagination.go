@@ -0,0 +1,123 @@
+package opperai
+
+import "context"
+
+const defaultPageSize = 100
+
+// ListOptions controls how list endpoints are paginated.
+type ListOptions struct {
+	// PageSize is the number of items requested per API call (default 100).
+	PageSize int
+	// Limit caps the total number of items returned. Zero means no limit.
+	Limit int
+}
+
+// page is a single page of results returned by a fetch function.
+type page[T any] struct {
+	items []T
+	more  bool
+	total int
+}
+
+// Iterator walks a paginated list endpoint, fetching pages as needed.
+//
+//	it := client.Functions.Iter(ctx, nil)
+//	for it.Next() {
+//		fn := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx      context.Context
+	fetch    func(ctx context.Context, pageSize int) (*page[T], error)
+	pageSize int
+	limit    int
+
+	buf   []T
+	cur   T
+	seen  int
+	more  bool
+	total int
+	err   error
+}
+
+func newIterator[T any](ctx context.Context, opts *ListOptions, fetch func(ctx context.Context, pageSize int) (*page[T], error)) *Iterator[T] {
+	it := &Iterator[T]{
+		ctx:      ctx,
+		fetch:    fetch,
+		pageSize: defaultPageSize,
+		more:     true,
+	}
+	if opts != nil {
+		if opts.PageSize > 0 {
+			it.pageSize = opts.PageSize
+		}
+		if opts.Limit > 0 {
+			it.limit = opts.Limit
+		}
+	}
+	return it
+}
+
+// Next advances to the next item, fetching a new page when the current one
+// is exhausted. It returns false when there are no more items or an error
+// occurred.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil || (it.limit > 0 && it.seen >= it.limit) {
+		return false
+	}
+
+	for len(it.buf) == 0 {
+		if !it.more {
+			return false
+		}
+
+		pageSize := it.pageSize
+		if it.limit > 0 && it.limit-it.seen < pageSize {
+			pageSize = it.limit - it.seen
+		}
+
+		p, err := it.fetch(it.ctx, pageSize)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.buf = p.items
+		it.more = p.more && len(p.items) > 0
+		if p.total > 0 {
+			it.total = p.total
+		}
+	}
+
+	it.cur = it.buf[0]
+	it.buf = it.buf[1:]
+	it.seen++
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err returns the first error encountered while fetching pages.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Total returns the total number of items reported by the server, or zero
+// if the endpoint does not report one. It is only set after the first call
+// to Next.
+func (it *Iterator[T]) Total() int {
+	return it.total
+}
+
+// All drains the iterator and returns the collected items.
+func (it *Iterator[T]) All() ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
@@ -0,0 +1,86 @@
+package opperai
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     int
+		opts      *ListOptions
+		failAfter int
+		wantCount int
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "all items across pages",
+			items:     25,
+			opts:      &ListOptions{PageSize: 10},
+			wantCount: 25,
+			wantCalls: 3,
+		},
+		{
+			name:      "limit stops early",
+			items:     25,
+			opts:      &ListOptions{PageSize: 10, Limit: 12},
+			wantCount: 12,
+			wantCalls: 2,
+		},
+		{
+			name:      "default options",
+			items:     5,
+			wantCount: 5,
+			wantCalls: 1,
+		},
+		{
+			name:      "error on second page",
+			items:     25,
+			opts:      &ListOptions{PageSize: 10},
+			failAfter: 1,
+			wantCount: 10,
+			wantCalls: 2,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, calls := 0, 0
+			it := newIterator(context.Background(), tt.opts, func(ctx context.Context, pageSize int) (*page[int], error) {
+				calls++
+				if tt.failAfter > 0 && calls > tt.failAfter {
+					return nil, errors.New("boom")
+				}
+				var items []int
+				for i := offset; i < offset+pageSize && i < tt.items; i++ {
+					items = append(items, i)
+				}
+				offset += len(items)
+				return &page[int]{items: items, more: offset < tt.items, total: tt.items}, nil
+			})
+
+			items, err := it.All()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("All() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(items) != tt.wantCount {
+				t.Errorf("expected %d items, got %d", tt.wantCount, len(items))
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d fetches, got %d", tt.wantCalls, calls)
+			}
+			for i, item := range items {
+				if item != i {
+					t.Errorf("expected item %d at position %d, got %d", i, i, item)
+				}
+			}
+			if it.Total() != tt.items {
+				t.Errorf("expected total %d, got %d", tt.items, it.Total())
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package opperai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RAGOptions controls how an index is used as context for a call.
+type RAGOptions struct {
+	// Index is the name of the index to query with the call input.
+	Index   string
+	Filters []Filter
+	// TopK is the number of results to use. Zero uses DefaultRAGTopK.
+	TopK int
+	// MinScore drops results scoring below it.
+	MinScore float64
+	Model    string
+	Tags     map[string]string
+}
+
+const DefaultRAGTopK = 5
+
+// Source is an index result given to the model as context. ID is the number
+// the model cites it by.
+type Source struct {
+	ID       int                    `json:"id"`
+	Key      string                 `json:"key"`
+	Content  string                 `json:"content"`
+	Score    float64                `json:"score"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// RAGResponse is the answer to a retrieval-augmented call with the sources it
+// was given.
+type RAGResponse struct {
+	Message string   `json:"message"`
+	Sources []Source `json:"sources"`
+}
+
+// Cited returns the sources referenced as [n] in the answer, or all sources
+// if the answer cites none.
+func (r *RAGResponse) Cited() []Source {
+	if cited := CitedSources(r.Message, r.Sources); len(cited) > 0 {
+		return cited
+	}
+	return r.Sources
+}
+
+const ragInstructions = "\n\nAnswer using the numbered sources provided with the question. " +
+	"Cite the sources you use inline by number, like [1] or [2][3]. " +
+	"If the sources do not contain the answer, say so instead of guessing."
+
+// CallWithIndex queries an index with the input, adds the top results to the
+// call as numbered sources and asks the model to cite them.
+func (c *CallClient) CallWithIndex(ctx context.Context, name string, instructions string, input string, opts RAGOptions) (*RAGResponse, error) {
+	if opts.Index == "" {
+		return nil, fmt.Errorf("index is required")
+	}
+	topK := opts.TopK
+	if topK <= 0 {
+		topK = DefaultRAGTopK
+	}
+
+	results, err := c.client.Indexes.QueryWithOptions(opts.Index, input, &QueryOptions{
+		Filters: opts.Filters,
+		TopK:    topK,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying index: %w", err)
+	}
+
+	var sources []Source
+	for _, r := range results {
+		if r.Score < opts.MinScore || len(sources) == topK {
+			continue
+		}
+		sources = append(sources, Source{
+			ID:       len(sources) + 1,
+			Key:      r.Key,
+			Content:  r.Content,
+			Score:    r.Score,
+			Metadata: r.Metadata,
+		})
+	}
+
+	response, err := c.Call(ctx, name, instructions+ragInstructions, BuildRAGInput(input, sources), opts.Model, false, opts.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RAGResponse{Message: response.Message, Sources: sources}, nil
+}
+
+// BuildRAGInput formats sources and the question as the input of a call.
+func BuildRAGInput(input string, sources []Source) string {
+	var sb strings.Builder
+	sb.WriteString("Sources:\n")
+	if len(sources) == 0 {
+		sb.WriteString("(no matching sources found)\n")
+	}
+	for _, s := range sources {
+		fmt.Fprintf(&sb, "\n[%d] %s\n%s\n", s.ID, s.Key, strings.TrimSpace(s.Content))
+	}
+	sb.WriteString("\nQuestion:\n")
+	sb.WriteString(input)
+	return sb.String()
+}
+
+var citationRe = regexp.MustCompile(`\[(\d+)\]`)
+
+// CitedSources returns the sources referenced as [n] in text, in source
+// order.
+func CitedSources(text string, sources []Source) []Source {
+	cited := map[int]bool{}
+	for _, m := range citationRe.FindAllStringSubmatch(text, -1) {
+		if id, err := strconv.Atoi(m[1]); err == nil {
+			cited[id] = true
+		}
+	}
+
+	var out []Source
+	for _, s := range sources {
+		if cited[s.ID] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
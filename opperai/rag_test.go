@@ -0,0 +1,88 @@
+package opperai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCallWithIndex(t *testing.T) {
+	var callInput, callInstructions string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/indexes/query/by-name/docs":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["q"] != "how do I install?" || body["k"] != float64(2) {
+				t.Errorf("unexpected query body %v", body)
+			}
+			json.NewEncoder(w).Encode([]RetrievalResponse{
+				{Key: "install.md#0", Content: "Run make install.", Score: 0.9},
+				{Key: "faq.md#3", Content: "Use brew.", Score: 0.7},
+				{Key: "old.md#1", Content: "Irrelevant.", Score: 0.1},
+			})
+		case "/v1/call":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			callInput, _ = body["input"].(string)
+			callInstructions, _ = body["instructions"].(string)
+			json.NewEncoder(w).Encode(map[string]string{"message": "Run make install [1]."})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	resp, err := client.Call.CallWithIndex(context.Background(), "support", "Answer questions", "how do I install?", RAGOptions{
+		Index:    "docs",
+		TopK:     2,
+		MinScore: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("CallWithIndex() error = %v", err)
+	}
+
+	if len(resp.Sources) != 2 || resp.Sources[0].ID != 1 || resp.Sources[1].Key != "faq.md#3" {
+		t.Errorf("unexpected sources %+v", resp.Sources)
+	}
+	if !strings.Contains(callInput, "[1] install.md#0\nRun make install.") || !strings.HasSuffix(callInput, "Question:\nhow do I install?") {
+		t.Errorf("sources missing from call input: %q", callInput)
+	}
+	if !strings.HasPrefix(callInstructions, "Answer questions") || !strings.Contains(callInstructions, "Cite the") {
+		t.Errorf("unexpected instructions %q", callInstructions)
+	}
+	if cited := resp.Cited(); len(cited) != 1 || cited[0].Key != "install.md#0" {
+		t.Errorf("Cited() = %+v", cited)
+	}
+}
+
+func TestCitedSources(t *testing.T) {
+	sources := []Source{{ID: 1, Key: "a"}, {ID: 2, Key: "b"}, {ID: 3, Key: "c"}}
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"no citations", nil},
+		{"see [3] and [1][3]", []string{"a", "c"}},
+		{"out of range [7]", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var got []string
+			for _, s := range CitedSources(tt.text, sources) {
+				got = append(got, s.Key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CitedSources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
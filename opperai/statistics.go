@@ -0,0 +1,34 @@
+package opperai
+
+import "sort"
+
+// Summarize computes summary statistics in the same shape the API reports
+// for evaluation dimensions.
+func Summarize(values []float64) StatisticsSummary {
+	if len(values) == 0 {
+		return StatisticsSummary{}
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+
+	n := len(sorted)
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	return StatisticsSummary{
+		Sum:    sum,
+		Count:  float64(n),
+		Min:    sorted[0],
+		Max:    sorted[n-1],
+		Avg:    sum / float64(n),
+		Median: median,
+	}
+}
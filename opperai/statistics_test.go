@@ -0,0 +1,35 @@
+package opperai
+
+import "testing"
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   StatisticsSummary
+	}{
+		{
+			name:   "empty",
+			values: nil,
+			want:   StatisticsSummary{},
+		},
+		{
+			name:   "odd count",
+			values: []float64{3, 1, 2},
+			want:   StatisticsSummary{Sum: 6, Count: 3, Min: 1, Max: 3, Avg: 2, Median: 2},
+		},
+		{
+			name:   "even count",
+			values: []float64{0.5, 1, 0, 0.25},
+			want:   StatisticsSummary{Sum: 1.75, Count: 4, Min: 0, Max: 1, Avg: 0.4375, Median: 0.375},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.values); got != tt.want {
+				t.Errorf("Summarize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
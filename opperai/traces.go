@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -15,11 +17,29 @@ func newTracesClient(client *Client) *TracesClient {
 	return &TracesClient{client: client}
 }
 
-// List returns all traces
+// List returns the most recent traces
 func (c *TracesClient) List(ctx context.Context, limit int) ([]Trace, error) {
-	path := "/v1/traces"
+	response, err := c.ListPage(ctx, limit, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Traces, nil
+}
+
+// ListPage returns a single page of traces starting at cursor
+func (c *TracesClient) ListPage(ctx context.Context, limit int, cursor string) (*TraceListResponse, error) {
+	params := url.Values{}
 	if limit > 0 {
-		path = fmt.Sprintf("%s?limit=%d", path, limit)
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+
+	path := "/v1/traces"
+	if len(params) > 0 {
+		path = fmt.Sprintf("%s?%s", path, params.Encode())
 	}
 
 	resp, err := c.client.DoRequest(ctx, "GET", path, nil)
@@ -37,7 +57,23 @@ func (c *TracesClient) List(ctx context.Context, limit int) ([]Trace, error) {
 		return nil, err
 	}
 
-	return response.Traces, nil
+	return &response, nil
+}
+
+// Iter returns an iterator over traces, following the cursor between pages
+func (c *TracesClient) Iter(ctx context.Context, opts *ListOptions) *Iterator[Trace] {
+	cursor := ""
+	return newIterator(ctx, opts, func(ctx context.Context, pageSize int) (*page[Trace], error) {
+		response, err := c.ListPage(ctx, pageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = response.Cursor
+		return &page[Trace]{
+			items: response.Traces,
+			more:  cursor != "",
+		}, nil
+	})
 }
 
 // Get returns a specific trace
@@ -72,6 +108,12 @@ type TraceWatcher interface {
 
 // Add these new methods to TracesClient
 func (c *TracesClient) WatchList(ctx context.Context, seenTraces map[string]bool) (<-chan TraceUpdate, error) {
+	return c.WatchListN(ctx, seenTraces, 10)
+}
+
+// WatchListN is like WatchList but checks the n most recent traces on each
+// poll, so up to n new traces are picked up per interval.
+func (c *TracesClient) WatchListN(ctx context.Context, seenTraces map[string]bool, n int) (<-chan TraceUpdate, error) {
 	updates := make(chan TraceUpdate)
 
 	go func() {
@@ -85,7 +127,7 @@ func (c *TracesClient) WatchList(ctx context.Context, seenTraces map[string]bool
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				traces, err := c.List(ctx, 10) // Only fetch the 10 most recent traces
+				traces, err := c.List(ctx, n) // Only fetch the n most recent traces
 				if err != nil {
 					updates <- TraceUpdate{Error: err}
 					continue
@@ -0,0 +1,54 @@
+package opperai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIterTraces(t *testing.T) {
+	pages := map[string]TraceListResponse{
+		"": {
+			Traces: []Trace{{UUID: "t1"}, {UUID: "t2"}},
+			Cursor: "c1",
+		},
+		"c1": {
+			Traces: []Trace{{UUID: "t3"}},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("expected path %s, got %s", "/v1/traces", r.URL.Path)
+		}
+		if r.URL.Query().Get("limit") != "2" {
+			t.Errorf("expected limit 2, got %s", r.URL.Query().Get("limit"))
+		}
+
+		response, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	traces, err := client.Traces.Iter(context.Background(), &ListOptions{PageSize: 2}).All()
+	if err != nil {
+		t.Fatalf("Iter() unexpected error = %v", err)
+	}
+
+	want := []string{"t1", "t2", "t3"}
+	if len(traces) != len(want) {
+		t.Fatalf("expected %d traces, got %d", len(want), len(traces))
+	}
+	for i, trace := range traces {
+		if trace.UUID != want[i] {
+			t.Errorf("expected trace %s, got %s", want[i], trace.UUID)
+		}
+	}
+}
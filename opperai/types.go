@@ -49,6 +49,38 @@ type FunctionDescription struct {
 	OutputSchema      map[string]interface{} `json:"out_schema"`
 	IndexConfig       *IndexConfig           `json:"index_config,omitempty"`
 	Metadata          map[string]string      `json:"metadata,omitempty"`
+	CreatedAt         string                 `json:"created_at,omitempty"`
+	UpdatedAt         string                 `json:"updated_at,omitempty"`
+}
+
+// FunctionListParams narrows down and orders the functions returned by the API.
+type FunctionListParams struct {
+	Project    string   `url:"project,omitempty"`
+	Model      string   `url:"model,omitempty"`
+	HasDataset bool     `url:"has_dataset,omitempty"`
+	Metadata   []string `url:"metadata,omitempty"` // key=value pairs
+	Sort       string   `url:"sort,omitempty"`     // field name, prefix with "-" for descending
+}
+
+// FunctionRevision is a snapshot of a function at a given revision.
+type FunctionRevision struct {
+	Revision     int                    `json:"revision"`
+	Instructions string                 `json:"instructions"`
+	Description  string                 `json:"description"`
+	Model        string                 `json:"model"`
+	InputSchema  map[string]interface{} `json:"input_schema"`
+	OutputSchema map[string]interface{} `json:"out_schema"`
+	CreatedAt    string                 `json:"created_at"`
+}
+
+// FunctionUpdate holds the fields to change on an existing function.
+// Empty fields are left untouched.
+type FunctionUpdate struct {
+	Instructions string                 `json:"instructions,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Model        string                 `json:"model,omitempty"`
+	InputSchema  map[string]interface{} `json:"input_schema,omitempty"`
+	OutputSchema map[string]interface{} `json:"out_schema,omitempty"`
 }
 
 type IndexConfig struct {
@@ -85,13 +117,36 @@ type Index struct {
 }
 
 type File struct {
-	ID               int       `json:"id"`
-	OriginalFilename string    `json:"original_filename"`
-	Size             int64     `json:"size"`
-	IndexStatus      string    `json:"index_status"`
-	Key              string    `json:"key"`
-	UUID             string    `json:"uuid"`
-	CreatedAt        time.Time `json:"created_at"`
+	ID                 int       `json:"id"`
+	OriginalFilename   string    `json:"original_filename"`
+	Size               int64     `json:"size"`
+	IndexStatus        string    `json:"index_status"`
+	IndexStatusMessage string    `json:"index_status_message,omitempty"`
+	Key                string    `json:"key"`
+	UUID               string    `json:"uuid"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+const (
+	FileStatusPending    = "pending"
+	FileStatusProcessing = "processing"
+	FileStatusCompleted  = "completed"
+	FileStatusFailed     = "failed"
+)
+
+// Done reports whether the file has left the pending and processing states,
+// successfully or not. Use Failed to tell the two apart.
+func (f File) Done() bool {
+	switch f.IndexStatus {
+	case "", FileStatusPending, FileStatusProcessing, "queued", "uploading", "indexing":
+		return false
+	}
+	return true
+}
+
+// Failed reports whether indexing the file failed.
+func (f File) Failed() bool {
+	return f.IndexStatus == FileStatusFailed || f.IndexStatus == "error"
 }
 
 type Document struct {
@@ -100,6 +155,13 @@ type Document struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+type DocumentsResponse struct {
+	Meta struct {
+		TotalCount int `json:"total_count"`
+	} `json:"meta"`
+	Data []Document `json:"data"`
+}
+
 type RetrievalResponse struct {
 	Key      string                 `json:"key"`
 	Content  string                 `json:"content"`
@@ -176,10 +238,75 @@ type Dataset struct {
 	EntryCount int    `json:"entry_count"`
 }
 
+// DatasetEntry is a labelled example in a function's dataset.
+type DatasetEntry struct {
+	UUID      string `json:"uuid,omitempty"`
+	Input     string `json:"input"`
+	Output    string `json:"output"` // Expected output
+	Comment   string `json:"comment,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+type DatasetEntriesResponse struct {
+	Meta struct {
+		TotalCount int `json:"total_count"`
+	} `json:"meta"`
+	Data []DatasetEntry `json:"data"`
+}
+
+// BuiltinLanguageModel is a model from the built-in catalog. Costs are in
+// USD per token; zero means the cost is not reported.
 type BuiltinLanguageModel struct {
-	Name            string `json:"name"`
-	HostingProvider string `json:"hosting_provider"`
-	Location        string `json:"location"`
+	Name               string   `json:"name"`
+	HostingProvider    string   `json:"hosting_provider"`
+	Location           string   `json:"location"`
+	Description        string   `json:"description,omitempty"`
+	ContextWindow      int      `json:"context_window,omitempty"`
+	MaxOutputTokens    int      `json:"max_output_tokens,omitempty"`
+	InputCostPerToken  float64  `json:"input_cost_per_token,omitempty"`
+	OutputCostPerToken float64  `json:"output_cost_per_token,omitempty"`
+	InputModalities    []string `json:"input_modalities,omitempty"`
+	OutputModalities   []string `json:"output_modalities,omitempty"`
+	Capabilities       []string `json:"capabilities,omitempty"`
+	Deprecated         bool     `json:"deprecated,omitempty"`
+	DeprecationDate    string   `json:"deprecation_date,omitempty"`
+	ReplacedBy         string   `json:"replaced_by,omitempty"`
+	// Extra holds fields returned by the API that are not decoded above
+	Extra map[string]interface{} `json:"extra,omitempty"`
+}
+
+func (m *BuiltinLanguageModel) UnmarshalJSON(data []byte) error {
+	type Alias BuiltinLanguageModel
+	aux := &struct {
+		*Alias
+	}{
+		Alias: (*Alias)(m),
+	}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	var rawMap map[string]interface{}
+	if err := json.Unmarshal(data, &rawMap); err != nil {
+		return err
+	}
+
+	// Keep unknown fields so newer catalog attributes are not lost
+	for k, v := range rawMap {
+		switch k {
+		case "name", "hosting_provider", "location", "description", "context_window",
+			"max_output_tokens", "input_cost_per_token", "output_cost_per_token",
+			"input_modalities", "output_modalities", "capabilities", "deprecated",
+			"deprecation_date", "replaced_by", "extra":
+			continue
+		}
+		if m.Extra == nil {
+			m.Extra = make(map[string]interface{})
+		}
+		m.Extra[k] = v
+	}
+
+	return nil
 }
 
 type EvaluationMetric struct {
@@ -193,6 +320,27 @@ type EvaluationStatus struct {
 	Details string `json:"details,omitempty"`
 }
 
+const (
+	EvaluationStatePending   = "pending"
+	EvaluationStateRunning   = "running"
+	EvaluationStateCompleted = "completed"
+	EvaluationStateFailed    = "failed"
+)
+
+// Done reports whether the evaluation has stopped running.
+func (s EvaluationStatus) Done() bool {
+	switch s.State {
+	case EvaluationStateCompleted, EvaluationStateFailed, "cancelled", "error":
+		return true
+	}
+	return false
+}
+
+// Failed reports whether the evaluation stopped without completing.
+func (s EvaluationStatus) Failed() bool {
+	return s.Done() && s.State != EvaluationStateCompleted
+}
+
 type EvaluationRecord struct {
 	EvaluationRecordUUID string                      `json:"evaluation_record_uuid"`
 	DatasetEntryUUID     string                      `json:"dataset_entry_uuid"`
@@ -212,10 +360,17 @@ type StatisticsSummary struct {
 	Median float64 `json:"median"`
 }
 
+// FunctionOverride replaces parts of a function's configuration for a
+// single evaluation run. Empty fields fall back to the function's settings.
 type FunctionOverride struct {
-	Model        string `json:"model"`
-	Instructions string `json:"instructions"`
-	FewShotCount *int   `json:"few_shot_count"`
+	Model        string `json:"model,omitempty"`
+	Instructions string `json:"instructions,omitempty"`
+	FewShotCount *int   `json:"few_shot_count,omitempty"`
+}
+
+// IsZero reports whether the override changes nothing.
+func (o FunctionOverride) IsZero() bool {
+	return o.Model == "" && o.Instructions == "" && o.FewShotCount == nil
 }
 
 type Evaluation struct {
@@ -237,6 +392,13 @@ type EvaluationsResponse struct {
 	Data []Evaluation `json:"data"`
 }
 
+type FunctionsResponse struct {
+	Meta struct {
+		TotalCount int `json:"total_count"`
+	} `json:"meta"`
+	Data []FunctionDescription `json:"data"`
+}
+
 type UsageStats struct {
 	TotalTokensInput  int     `json:"total_tokens_input"`
 	TotalTokensOutput int     `json:"total_tokens_output"`